@@ -0,0 +1,109 @@
+package awsclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// AuroraClusterInfo is an Aurora cluster with its cluster-level storage and
+// I/O usage and the monthly cost of the cluster and of each member instance.
+type AuroraClusterInfo struct {
+	ClusterID                     string               `json:"clusterId"`
+	ClusterArn                    *string              `json:"clusterArn"`
+	Region                        string               `json:"region"`
+	Engine                        string               `json:"engine"`
+	EngineVersion                 string               `json:"engineVersion"`
+	EngineMode                    string               `json:"engineMode"`
+	Status                        string               `json:"status"`
+	MultiAZ                       bool                 `json:"multiAZ"`
+	IsNonProd                     bool                 `json:"isNonProd"`
+	StorageType                   string               `json:"storageType"`
+	ServerlessMinACU              float64              `json:"serverlessMinACU"`
+	ServerlessMaxACU              float64              `json:"serverlessMaxACU"`
+	Tags                          map[string]string    `json:"tags"`
+	WriterID                      string               `json:"writerId"`
+	ReaderIDs                     []string             `json:"readerIds"`
+	Instances                     []AuroraInstanceCost `json:"instances"`
+	StorageGB                     float64              `json:"storageGB"`
+	ReadIOsPerMonth               float64              `json:"readIOsPerMonth"`
+	WriteIOsPerMonth              float64              `json:"writeIOsPerMonth"`
+	BackupStorageBilledGB         float64              `json:"backupStorageBilledGB"`
+	InstancesMonthlyCost          float64              `json:"instancesMonthlyCost"`
+	StorageMonthlyCost            float64              `json:"storageMonthlyCost"`
+	IOMonthlyCost                 float64              `json:"ioMonthlyCost"`
+	BackupMonthlyCost             float64              `json:"backupMonthlyCost"`
+	StandardMonthlyCost           float64              `json:"standardMonthlyCost"`
+	IOOptimizedMonthlyCost        float64              `json:"ioOptimizedMonthlyCost"`
+	IOSharePct                    float64              `json:"ioSharePct"`
+	RecommendedStorageType        string               `json:"recommendedStorageType"`
+	StorageConfigSavings          float64              `json:"storageConfigSavings"`
+	StorageConfigRecommendation   string               `json:"storageConfigRecommendation"`
+	MultiAZSavings                float64              `json:"multiAZSavings"`
+	MultiAZRecommendation         string               `json:"multiAZRecommendation"`
+	BackupRetentionDays           int32                `json:"backupRetentionDays"`
+	SnapshotCount                 int                  `json:"snapshotCount"`
+	SnapshotMonthlyCost           float64              `json:"snapshotMonthlyCost"`
+	BackupRetentionSavings        float64              `json:"backupRetentionSavings"`
+	BackupRetentionRecommendation string               `json:"backupRetentionRecommendation"`
+	TotalMonthlyCost              float64              `json:"totalMonthlyCost"`
+	PotentialSavings              float64              `json:"potentialSavings"`
+	Recommendation                string               `json:"recommendation"`
+	NeedOptimisation              bool                 `json:"needOptimisation"`
+}
+
+// AuroraInstanceCost is the compute cost of one member of an Aurora cluster.
+type AuroraInstanceCost struct {
+	InstanceID         string  `json:"instanceId"`
+	InstanceClass      string  `json:"instanceClass"`
+	Role               string  `json:"role"`
+	ComputeMonthlyCost float64 `json:"computeMonthlyCost"`
+}
+
+// NewAuroraClusterInfo maps the DescribeDBClusters description of a cluster
+// onto AuroraClusterInfo. Usage and cost are filled in by the caller.
+func NewAuroraClusterInfo(cluster rdstypes.DBCluster) AuroraClusterInfo {
+	info := AuroraClusterInfo{
+		ClusterID:           aws.ToString(cluster.DBClusterIdentifier),
+		ClusterArn:          cluster.DBClusterArn,
+		Engine:              aws.ToString(cluster.Engine),
+		EngineVersion:       aws.ToString(cluster.EngineVersion),
+		EngineMode:          aws.ToString(cluster.EngineMode),
+		Status:              aws.ToString(cluster.Status),
+		MultiAZ:             aws.ToBool(cluster.MultiAZ),
+		BackupRetentionDays: aws.ToInt32(cluster.BackupRetentionPeriod),
+		StorageType:         aws.ToString(cluster.StorageType),
+		Tags:                map[string]string{},
+	}
+	if info.StorageType == "" {
+		info.StorageType = "aurora"
+	}
+	if scaling := cluster.ServerlessV2ScalingConfiguration; scaling != nil {
+		info.ServerlessMinACU = aws.ToFloat64(scaling.MinCapacity)
+		info.ServerlessMaxACU = aws.ToFloat64(scaling.MaxCapacity)
+	}
+	for _, tag := range cluster.TagList {
+		info.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	for _, member := range cluster.DBClusterMembers {
+		id := aws.ToString(member.DBInstanceIdentifier)
+		if aws.ToBool(member.IsClusterWriter) {
+			info.WriterID = id
+		} else {
+			info.ReaderIDs = append(info.ReaderIDs, id)
+		}
+	}
+	return info
+}
+
+// GetRDSClusterSeries returns the hourly series of a cluster metric; see
+// GetHourlySeries for how missing hours are reported.
+func GetRDSClusterSeries(ctx context.Context, cw *cloudwatch.Client, clusterID, metric string, stat types.Statistic, start time.Time, hours int) ([]float64, error) {
+	return GetHourlySeries(ctx, cw, "AWS/RDS", metric, []types.Dimension{
+		{Name: aws.String("DBClusterIdentifier"), Value: aws.String(clusterID)},
+	}, stat, start, hours)
+}
@@ -0,0 +1,120 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling/types"
+)
+
+// AutoScalingSettings is the Application Auto Scaling configuration of one
+// DynamoDB capacity dimension (table or GSI, read or write).
+type AutoScalingSettings struct {
+	ResourceID        string  `json:"resourceId"`
+	ScalableDimension string  `json:"scalableDimension"`
+	MinCapacity       int32   `json:"minCapacity"`
+	MaxCapacity       int32   `json:"maxCapacity"`
+	TargetUtilization float64 `json:"targetUtilization"`
+	ScaleInCooldown   int32   `json:"scaleInCooldown"`
+	ScaleOutCooldown  int32   `json:"scaleOutCooldown"`
+	DisableScaleIn    bool    `json:"disableScaleIn"`
+}
+
+// AutoScalingAdvice is the recommended autoscaling configuration for one
+// capacity dimension, with costs over the lookback window.
+type AutoScalingAdvice struct {
+	ScalableDimension string  `json:"scalableDimension"`
+	CurrentMin        int32   `json:"currentMin"`
+	CurrentMax        int32   `json:"currentMax"`
+	CurrentTarget     float64 `json:"currentTarget"`
+	RecommendedMin    int32   `json:"recommendedMin"`
+	RecommendedMax    int32   `json:"recommendedMax"`
+	RecommendedTarget float64 `json:"recommendedTarget"`
+	CurrentCost       float64 `json:"currentCost"`
+	ProjectedCost     float64 `json:"projectedCost"`
+	PotentialSavings  float64 `json:"potentialSavings"`
+}
+
+// DynamoDBTableResourceID is the Application Auto Scaling resource id of a table.
+func DynamoDBTableResourceID(tableName string) string {
+	return "table/" + tableName
+}
+
+// DynamoDBIndexResourceID is the Application Auto Scaling resource id of a GSI.
+func DynamoDBIndexResourceID(tableName, indexName string) string {
+	return fmt.Sprintf("table/%s/index/%s", tableName, indexName)
+}
+
+// GetDynamoDBScaling returns the read and write autoscaling settings of a
+// table or index resource id; either is nil when that dimension is not scaled.
+// Targets and policies for the whole account are loaded once and cached.
+func (c *AWSClient) GetDynamoDBScaling(ctx context.Context, resourceID string) (read, write *AutoScalingSettings) {
+	c.scalingOnce.Do(func() {
+		c.dynamoScaling = c.loadDynamoDBScaling(ctx)
+	})
+
+	for _, s := range c.dynamoScaling[resourceID] {
+		switch types.ScalableDimension(s.ScalableDimension) {
+		case types.ScalableDimensionDynamoDBTableReadCapacityUnits, types.ScalableDimensionDynamoDBIndexReadCapacityUnits:
+			read = s
+		case types.ScalableDimensionDynamoDBTableWriteCapacityUnits, types.ScalableDimensionDynamoDBIndexWriteCapacityUnits:
+			write = s
+		}
+	}
+	return read, write
+}
+
+func (c *AWSClient) loadDynamoDBScaling(ctx context.Context) map[string][]*AutoScalingSettings {
+	log.Println("Fetching DynamoDB autoscaling targets...")
+	settings := map[string][]*AutoScalingSettings{}
+	byKey := map[string]*AutoScalingSettings{}
+
+	targets := applicationautoscaling.NewDescribeScalableTargetsPaginator(c.AutoScaling, &applicationautoscaling.DescribeScalableTargetsInput{
+		ServiceNamespace: types.ServiceNamespaceDynamodb,
+	})
+	for targets.HasMorePages() {
+		page, err := targets.NextPage(ctx)
+		if err != nil {
+			log.Printf("Error describing scalable targets: %v\n", err)
+			return settings
+		}
+		for _, t := range page.ScalableTargets {
+			s := &AutoScalingSettings{
+				ResourceID:        aws.ToString(t.ResourceId),
+				ScalableDimension: string(t.ScalableDimension),
+				MinCapacity:       aws.ToInt32(t.MinCapacity),
+				MaxCapacity:       aws.ToInt32(t.MaxCapacity),
+			}
+			settings[s.ResourceID] = append(settings[s.ResourceID], s)
+			byKey[s.ResourceID+"|"+s.ScalableDimension] = s
+		}
+	}
+
+	policies := applicationautoscaling.NewDescribeScalingPoliciesPaginator(c.AutoScaling, &applicationautoscaling.DescribeScalingPoliciesInput{
+		ServiceNamespace: types.ServiceNamespaceDynamodb,
+	})
+	for policies.HasMorePages() {
+		page, err := policies.NextPage(ctx)
+		if err != nil {
+			log.Printf("Error describing scaling policies: %v\n", err)
+			return settings
+		}
+		for _, p := range page.ScalingPolicies {
+			cfg := p.TargetTrackingScalingPolicyConfiguration
+			s := byKey[aws.ToString(p.ResourceId)+"|"+string(p.ScalableDimension)]
+			if cfg == nil || s == nil {
+				continue
+			}
+			s.TargetUtilization = aws.ToFloat64(cfg.TargetValue)
+			s.ScaleInCooldown = aws.ToInt32(cfg.ScaleInCooldown)
+			s.ScaleOutCooldown = aws.ToInt32(cfg.ScaleOutCooldown)
+			s.DisableScaleIn = aws.ToBool(cfg.DisableScaleIn)
+		}
+	}
+
+	log.Printf("Got autoscaling targets %d", len(byKey))
+	return settings
+}
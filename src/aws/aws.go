@@ -2,15 +2,20 @@ package awsclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 )
 
 type AWSClientOpts struct {
@@ -19,9 +24,17 @@ type AWSClientOpts struct {
 }
 
 type AWSClient struct {
-	DynamoDB   *dynamodb.Client
-	CloudWatch *cloudwatch.Client
-	RDS        *rds.Client
+	Region      string
+	DynamoDB    *dynamodb.Client
+	CloudWatch  *cloudwatch.Client
+	RDS         *rds.Client
+	AutoScaling *applicationautoscaling.Client
+
+	cfg              aws.Config
+	regionCloudWatch sync.Map // region -> *cloudwatch.Client
+	regionRDS        sync.Map // region -> *rds.Client
+	scalingOnce      sync.Once
+	dynamoScaling    map[string][]*AutoScalingSettings
 }
 
 func NewAWSClient(opts AWSClientOpts) *AWSClient {
@@ -31,13 +44,48 @@ func NewAWSClient(opts AWSClientOpts) *AWSClient {
 	}
 
 	return &AWSClient{
-		DynamoDB:   dynamodb.NewFromConfig(cfg),
-		CloudWatch: cloudwatch.NewFromConfig(cfg),
-		RDS:        rds.NewFromConfig(cfg),
+		Region:      opts.Region,
+		cfg:         cfg,
+		DynamoDB:    dynamodb.NewFromConfig(cfg),
+		CloudWatch:  cloudwatch.NewFromConfig(cfg),
+		RDS:         rds.NewFromConfig(cfg),
+		AutoScaling: applicationautoscaling.NewFromConfig(cfg),
 	}
 
 }
 
+// CloudWatchIn returns a CloudWatch client for another region, e.g. to read
+// the metrics of a global table replica.
+func (c *AWSClient) CloudWatchIn(region string) *cloudwatch.Client {
+	if region == c.Region {
+		return c.CloudWatch
+	}
+	if cw, ok := c.regionCloudWatch.Load(region); ok {
+		return cw.(*cloudwatch.Client)
+	}
+	cw := cloudwatch.NewFromConfig(c.cfg, func(o *cloudwatch.Options) {
+		o.Region = region
+	})
+	c.regionCloudWatch.Store(region, cw)
+	return cw
+}
+
+// RDSIn returns an RDS client for another region, e.g. to look up the source
+// of a snapshot copied from there.
+func (c *AWSClient) RDSIn(region string) *rds.Client {
+	if region == "" || region == c.Region {
+		return c.RDS
+	}
+	if client, ok := c.regionRDS.Load(region); ok {
+		return client.(*rds.Client)
+	}
+	client := rds.NewFromConfig(c.cfg, func(o *rds.Options) {
+		o.Region = region
+	})
+	c.regionRDS.Store(region, client)
+	return client
+}
+
 func (c *AWSClient) GetDynamoDbTables(ctx context.Context) ([]string, error) {
 
 	var allTables []string
@@ -84,6 +132,11 @@ func (c *AWSClient) ProcessTable(ctx context.Context, timeFrameDays int, tableNa
 		billing = string(t.BillingModeSummary.BillingMode)
 	}
 
+	tableClass := "STANDARD"
+	if t.TableClassSummary != nil {
+		tableClass = string(t.TableClassSummary.TableClass)
+	}
+
 	var readCap, writeCap int64
 	if billing == "PROVISIONED" && t.ProvisionedThroughput != nil {
 		readCap = *t.ProvisionedThroughput.ReadCapacityUnits
@@ -100,6 +153,7 @@ func (c *AWSClient) ProcessTable(ctx context.Context, timeFrameDays int, tableNa
 	tableInfo := TableInfo{
 		TableName:          tableName,
 		BillingMode:        billing,
+		TableClass:         tableClass,
 		ItemCount:          *t.ItemCount,
 		TableSizeMB:        *t.TableSizeBytes / 1024 / 1024,
 		ReadCapacityUnits:  readCap,
@@ -107,21 +161,201 @@ func (c *AWSClient) ProcessTable(ctx context.Context, timeFrameDays int, tableNa
 		AvgConsumedRead:    readUsage,
 		AvgConsumedWrite:   writeUsage,
 		MetricsAvailable:   metricsAvailable,
+		LookbackHours:      24 * timeFrameDays,
 		TableArn:           t.TableArn,
+		CreationDateTime:   t.CreationDateTime,
+	}
+	if t.ProvisionedThroughput != nil {
+		tableInfo.LastIncreaseDateTime = t.ProvisionedThroughput.LastIncreaseDateTime
+		tableInfo.LastDecreaseDateTime = t.ProvisionedThroughput.LastDecreaseDateTime
+	}
+
+	hours := 24 * timeFrameDays
+	var readErr, writeErr error
+	tableInfo.ConsumedReadSeries, readErr = GetConsumedCapacitySeries(ctx, c.CloudWatch, tableName, "", "ConsumedReadCapacityUnits", start, hours)
+	tableInfo.ConsumedWriteSeries, writeErr = GetConsumedCapacitySeries(ctx, c.CloudWatch, tableName, "", "ConsumedWriteCapacityUnits", start, hours)
+	tableInfo.UsageSeriesAvailable = readErr == nil && writeErr == nil
+	if err := errors.Join(readErr, writeErr); err != nil {
+		log.Printf("Error reading consumed capacity of %s: %v\n", tableName, err)
+	}
+
+	tableInfo.ReadThrottleEvents, _ = GetSumMetric(ctx, c.CloudWatch, tableName, "", "ReadThrottleEvents", start, end)
+	tableInfo.WriteThrottleEvents, _ = GetSumMetric(ctx, c.CloudWatch, tableName, "", "WriteThrottleEvents", start, end)
+	tableInfo.ThrottledRequests, _ = GetThrottledRequests(ctx, c.CloudWatch, tableName, start, end)
+
+	if billing == "PROVISIONED" {
+		tableInfo.ReadAutoScaling, tableInfo.WriteAutoScaling = c.GetDynamoDBScaling(ctx, DynamoDBTableResourceID(tableName))
+		if tableInfo.ReadAutoScaling != nil {
+			tableInfo.ProvisionedReadSeries, _ = GetProvisionedCapacitySeries(ctx, c.CloudWatch, tableName, "", "ProvisionedReadCapacityUnits", start, hours)
+		}
+		if tableInfo.WriteAutoScaling != nil {
+			tableInfo.ProvisionedWriteSeries, _ = GetProvisionedCapacitySeries(ctx, c.CloudWatch, tableName, "", "ProvisionedWriteCapacityUnits", start, hours)
+		}
 	}
 
 	cost := EstimateDynamoDBCost(
 		float64(readCap),
 		float64(writeCap),
 		*t.TableSizeBytes,
-		24*timeFrameDays,
+		hours,
 		0, 0, 0,
 	)
 
+	for _, gsi := range t.GlobalSecondaryIndexes {
+		indexInfo := c.processIndex(ctx, billing, tableName, gsi, timeFrameDays, start, end)
+		cost += indexInfo.EstimatedCost
+		tableInfo.GSIs = append(tableInfo.GSIs, indexInfo)
+	}
+
+	for _, replica := range t.Replicas {
+		if aws.ToString(replica.RegionName) == c.Region {
+			continue
+		}
+		tableInfo.Replicas = append(tableInfo.Replicas, c.processReplica(ctx, tableName, readCap, replica, start, hours))
+	}
+
+	if spec := t.StreamSpecification; spec != nil && aws.ToBool(spec.StreamEnabled) {
+		tableInfo.StreamEnabled = true
+		tableInfo.StreamViewType = string(spec.StreamViewType)
+		tableInfo.StreamArn = t.LatestStreamArn
+		tableInfo.StreamReadRequests, err = GetStreamReadRequests(ctx, c.CloudWatch, tableName, aws.ToString(t.LatestStreamLabel), start, end)
+		if err != nil {
+			log.Printf("Error reading stream metrics of %s: %v\n", tableName, err)
+		}
+		tableInfo.StreamMetricsAvailable = err == nil
+	}
+	tableInfo.KinesisDestinations, err = c.GetKinesisDestinations(ctx, tableName)
+	if err != nil {
+		log.Printf("Error describing Kinesis destinations of %s: %v\n", tableName, err)
+	}
+
+	tableInfo.PITREnabled, err = c.GetPITREnabled(ctx, tableName)
+	if err != nil {
+		log.Printf("Error describing continuous backups of %s: %v\n", tableName, err)
+	}
+	tableInfo.Backups, err = c.GetTableBackups(ctx, tableName)
+	if err != nil {
+		log.Printf("Error listing backups of %s: %v\n", tableName, err)
+	}
+
+	tableInfo.LastConsumedAt = lastActivity(start, tableInfo.ConsumedReadSeries, tableInfo.ConsumedWriteSeries)
+	for _, gsi := range tableInfo.GSIs {
+		if at := lastActivity(start, gsi.ConsumedReadSeries, gsi.ConsumedWriteSeries); at != nil &&
+			(tableInfo.LastConsumedAt == nil || at.After(*tableInfo.LastConsumedAt)) {
+			tableInfo.LastConsumedAt = at
+		}
+	}
+
 	tableInfo.EstimatedCost = fmt.Sprintf("$%.2f", cost)
 	return tableInfo
 }
 
+// processReplica reads the consumed capacity of a global table replica from
+// CloudWatch in the replica's region.
+func (c *AWSClient) processReplica(ctx context.Context, tableName string, readCap int64, replica dynamotypes.ReplicaDescription, start time.Time, hours int) ReplicaInfo {
+	region := aws.ToString(replica.RegionName)
+	info := ReplicaInfo{
+		RegionName:        region,
+		ReplicaStatus:     string(replica.ReplicaStatus),
+		ReadCapacityUnits: readCap,
+	}
+	if o := replica.ProvisionedThroughputOverride; o != nil && o.ReadCapacityUnits != nil {
+		info.ReadCapacityUnits = *o.ReadCapacityUnits
+	}
+
+	cw := c.CloudWatchIn(region)
+	reads, err := GetConsumedCapacitySeries(ctx, cw, tableName, "", "ConsumedReadCapacityUnits", start, hours)
+	if err != nil {
+		log.Printf("No read metrics for replica %s in %s: %v\n", tableName, region, err)
+	}
+	info.MetricsAvailable = err == nil
+
+	for _, r := range reads {
+		info.ReadRequestUnits += r * 3600
+	}
+	if hours > 0 {
+		info.AvgConsumedRead = info.ReadRequestUnits / 3600 / float64(hours)
+	}
+	return info
+}
+
+// lastActivity returns the start of the last hour with consumed capacity in
+// any of the hourly series beginning at start, or nil if there was none.
+func lastActivity(start time.Time, series ...[]float64) *time.Time {
+	last := -1
+	for _, s := range series {
+		for h := len(s) - 1; h > last; h-- {
+			if s[h] > 0 {
+				last = h
+				break
+			}
+		}
+	}
+	if last < 0 {
+		return nil
+	}
+	return aws.Time(start.Add(time.Duration(last) * time.Hour))
+}
+
+func (c *AWSClient) processIndex(ctx context.Context, billing, tableName string, gsi dynamotypes.GlobalSecondaryIndexDescription, timeFrameDays int, start, end time.Time) GSIInfo {
+	indexName := aws.ToString(gsi.IndexName)
+
+	var readCap, writeCap int64
+	if billing == "PROVISIONED" && gsi.ProvisionedThroughput != nil {
+		readCap = aws.ToInt64(gsi.ProvisionedThroughput.ReadCapacityUnits)
+		writeCap = aws.ToInt64(gsi.ProvisionedThroughput.WriteCapacityUnits)
+	}
+
+	readUsage, err1 := GetIndexAvgMetric(ctx, c.CloudWatch, tableName, indexName, "ConsumedReadCapacityUnits", start, end)
+	writeUsage, err2 := GetIndexAvgMetric(ctx, c.CloudWatch, tableName, indexName, "ConsumedWriteCapacityUnits", start, end)
+
+	sizeBytes := aws.ToInt64(gsi.IndexSizeBytes)
+	hours := 24 * timeFrameDays
+	cost := EstimateDynamoDBCost(
+		float64(readCap),
+		float64(writeCap),
+		sizeBytes,
+		hours,
+		0, 0, 0,
+	)
+
+	indexInfo := GSIInfo{
+		IndexName:          indexName,
+		IndexArn:           gsi.IndexArn,
+		ItemCount:          aws.ToInt64(gsi.ItemCount),
+		IndexSizeMB:        sizeBytes / 1024 / 1024,
+		ReadCapacityUnits:  readCap,
+		WriteCapacityUnits: writeCap,
+		AvgConsumedRead:    readUsage,
+		AvgConsumedWrite:   writeUsage,
+		MetricsAvailable:   err1 == nil && err2 == nil,
+		EstimatedCost:      cost,
+	}
+
+	var readErr, writeErr error
+	indexInfo.ConsumedReadSeries, readErr = GetConsumedCapacitySeries(ctx, c.CloudWatch, tableName, indexName, "ConsumedReadCapacityUnits", start, hours)
+	indexInfo.ConsumedWriteSeries, writeErr = GetConsumedCapacitySeries(ctx, c.CloudWatch, tableName, indexName, "ConsumedWriteCapacityUnits", start, hours)
+	indexInfo.UsageSeriesAvailable = readErr == nil && writeErr == nil
+	if err := errors.Join(readErr, writeErr); err != nil {
+		log.Printf("Error reading consumed capacity of %s/%s: %v\n", tableName, indexName, err)
+	}
+
+	indexInfo.ReadThrottleEvents, _ = GetSumMetric(ctx, c.CloudWatch, tableName, indexName, "ReadThrottleEvents", start, end)
+	indexInfo.WriteThrottleEvents, _ = GetSumMetric(ctx, c.CloudWatch, tableName, indexName, "WriteThrottleEvents", start, end)
+
+	if billing == "PROVISIONED" {
+		indexInfo.ReadAutoScaling, indexInfo.WriteAutoScaling = c.GetDynamoDBScaling(ctx, DynamoDBIndexResourceID(tableName, indexName))
+		if indexInfo.ReadAutoScaling != nil {
+			indexInfo.ProvisionedReadSeries, _ = GetProvisionedCapacitySeries(ctx, c.CloudWatch, tableName, indexName, "ProvisionedReadCapacityUnits", start, hours)
+		}
+		if indexInfo.WriteAutoScaling != nil {
+			indexInfo.ProvisionedWriteSeries, _ = GetProvisionedCapacitySeries(ctx, c.CloudWatch, tableName, indexName, "ProvisionedWriteCapacityUnits", start, hours)
+		}
+	}
+
+	return indexInfo
+}
+
 // -------------------
 // RDS FUNCTIONS
 // -------------------
@@ -153,6 +387,118 @@ func (c *AWSClient) GetRDSInstances(ctx context.Context) []string {
 	return allInstances
 }
 
+// GetAuroraClusters returns the Aurora clusters of the region; Multi-AZ DB
+// clusters of other engines are left out, their storage is billed per instance.
+func (c *AWSClient) GetAuroraClusters(ctx context.Context) []rdstypes.DBCluster {
+	log.Println("Fetching Aurora clusters...")
+	var clusters []rdstypes.DBCluster
+	var marker *string
+
+	for {
+		out, err := c.RDS.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{
+			Marker: marker,
+		})
+		if err != nil {
+			log.Printf("Failed to describe RDS clusters: %v\n", err)
+			return clusters
+		}
+
+		for _, cluster := range out.DBClusters {
+			if IsAurora(aws.ToString(cluster.Engine)) {
+				clusters = append(clusters, cluster)
+			}
+		}
+
+		if out.Marker == nil {
+			break
+		}
+		marker = out.Marker
+	}
+
+	log.Printf("Got Aurora clusters %d", len(clusters))
+	return clusters
+}
+
+// GetRDSSnapshots returns the manual and automated snapshots of RDS instances.
+func (c *AWSClient) GetRDSSnapshots(ctx context.Context) []rdstypes.DBSnapshot {
+	log.Println("Fetching RDS snapshots...")
+	var snapshots []rdstypes.DBSnapshot
+	var marker *string
+
+	for {
+		out, err := c.RDS.DescribeDBSnapshots(ctx, &rds.DescribeDBSnapshotsInput{
+			Marker: marker,
+		})
+		if err != nil {
+			log.Printf("Failed to describe RDS snapshots: %v\n", err)
+			return snapshots
+		}
+
+		snapshots = append(snapshots, out.DBSnapshots...)
+
+		if out.Marker == nil {
+			break
+		}
+		marker = out.Marker
+	}
+
+	log.Printf("Got RDS snapshots %d", len(snapshots))
+	return snapshots
+}
+
+// GetRDSClusterSnapshots returns the manual and automated snapshots of
+// Aurora and Multi-AZ DB clusters.
+func (c *AWSClient) GetRDSClusterSnapshots(ctx context.Context) []rdstypes.DBClusterSnapshot {
+	log.Println("Fetching RDS cluster snapshots...")
+	var snapshots []rdstypes.DBClusterSnapshot
+	var marker *string
+
+	for {
+		out, err := c.RDS.DescribeDBClusterSnapshots(ctx, &rds.DescribeDBClusterSnapshotsInput{
+			Marker: marker,
+		})
+		if err != nil {
+			log.Printf("Failed to describe RDS cluster snapshots: %v\n", err)
+			return snapshots
+		}
+
+		snapshots = append(snapshots, out.DBClusterSnapshots...)
+
+		if out.Marker == nil {
+			break
+		}
+		marker = out.Marker
+	}
+
+	log.Printf("Got RDS cluster snapshots %d", len(snapshots))
+	return snapshots
+}
+
+// RDSSourceExists asks RDS whether the instance or cluster a snapshot was
+// taken from still exists in region. It only returns false when RDS reports
+// the source as not found; any other failure is returned as an error.
+func (c *AWSClient) RDSSourceExists(ctx context.Context, region, id string, cluster bool) (bool, error) {
+	client := c.RDSIn(region)
+	var err error
+	if cluster {
+		_, err = client.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(id)})
+		var notFound *rdstypes.DBClusterNotFoundFault
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+	} else {
+		_, err = client.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(id)})
+		var notFound *rdstypes.DBInstanceNotFoundFault
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (c *AWSClient) GetCloudWatchMetrics(ctx context.Context, namespace string) ([]CloudWatchMetricInfo, error) {
 	input := &cloudwatch.ListMetricsInput{
 		Namespace: &namespace,
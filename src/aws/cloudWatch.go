@@ -1,6 +1,16 @@
 package awsclient
 
-import "math"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
 
 type CloudWatchMetricInfo struct {
 	Namespace     string   `json:"namespace"`
@@ -22,3 +32,87 @@ func (c *AWSClient) EstimateCloudWatchMonthlyCost(metricsCount int, apiRequests
 
 	return math.Round((metricsCost+apiCost)*100) / 100 // rounded to cents
 }
+
+// ErrNoDatapoints is returned when CloudWatch has no datapoints for a metric
+// in the window, as opposed to a failed call.
+var ErrNoDatapoints = errors.New("no datapoints")
+
+// GetHourlySeries returns one value per hour of the window starting at start,
+// using the given statistic. Hours without a datapoint are NaN so callers can
+// choose how to fill them (see ZeroFill and ForwardFill).
+func GetHourlySeries(ctx context.Context, cw *cloudwatch.Client, namespace, metric string, dimensions []types.Dimension, stat types.Statistic, start time.Time, hours int) ([]float64, error) {
+	out, err := cw.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String(namespace),
+		MetricName: aws.String(metric),
+		Dimensions: dimensions,
+		StartTime:  aws.Time(start),
+		EndTime:    aws.Time(start.Add(time.Duration(hours) * time.Hour)),
+		Period:     aws.Int32(3600),
+		Statistics: []types.Statistic{stat},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetMetricStatistics for %s failed: %w", metric, err)
+	}
+	if len(out.Datapoints) == 0 {
+		return nil, fmt.Errorf("%w for %s", ErrNoDatapoints, metric)
+	}
+
+	series := make([]float64, hours)
+	for i := range series {
+		series[i] = math.NaN()
+	}
+	for _, dp := range out.Datapoints {
+		h := int(dp.Timestamp.Sub(start) / time.Hour)
+		if h < 0 || h >= hours {
+			continue
+		}
+		series[h] = datapointValue(dp, stat)
+	}
+	return series, nil
+}
+
+func datapointValue(dp types.Datapoint, stat types.Statistic) float64 {
+	switch stat {
+	case types.StatisticSum:
+		return aws.ToFloat64(dp.Sum)
+	case types.StatisticMaximum:
+		return aws.ToFloat64(dp.Maximum)
+	case types.StatisticMinimum:
+		return aws.ToFloat64(dp.Minimum)
+	case types.StatisticSampleCount:
+		return aws.ToFloat64(dp.SampleCount)
+	default:
+		return aws.ToFloat64(dp.Average)
+	}
+}
+
+// ZeroFill treats missing hours as zero, which is right for usage metrics
+// that CloudWatch only emits when there is activity.
+func ZeroFill(series []float64) []float64 {
+	for i, v := range series {
+		if math.IsNaN(v) {
+			series[i] = 0
+		}
+	}
+	return series
+}
+
+// ForwardFill repeats the last known value over missing hours, which is right
+// for gauges such as provisioned capacity.
+func ForwardFill(series []float64) []float64 {
+	last := math.NaN()
+	for _, v := range series {
+		if !math.IsNaN(v) {
+			last = v
+			break
+		}
+	}
+	for i, v := range series {
+		if math.IsNaN(v) {
+			series[i] = last
+		} else {
+			last = v
+		}
+	}
+	return ZeroFill(series)
+}
@@ -2,6 +2,7 @@ package awsclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,39 +12,215 @@ import (
 )
 
 type TableInfo struct {
-	TableName          string  `json:"tableName"`
-	BillingMode        string  `json:"billingMode"`
-	ItemCount          int64   `json:"itemCount"`
-	TableSizeMB        int64   `json:"tableSizeMB"`
-	ReadCapacityUnits  int64   `json:"readCapacityUnits"`
-	WriteCapacityUnits int64   `json:"writeCapacityUnits"`
-	AvgConsumedRead    float64 `json:"avgConsumedRead"`
-	AvgConsumedWrite   float64 `json:"avgConsumedWrite"`
-	MetricsAvailable   bool    `json:"metricsAvailable"`
-	TableArn           *string `json:"tableArn"`
-	EstimatedCost      string  `json:"estimatedCost"`
-	UtilizationPct     float64 `json:"utilizationPct"`
-	CurrentCost        float64 `json:"currentCost"`
-	ActualCost         float64 `json:"actualCost"`
-	PotentialSavings   float64 `json:"potentialSavings"`
-	PotentialSavingsP  float64 `json:"potentialSavingsP"`
-	Recommendation     string  `json:"recommendation"`
-	NeedOptimisation   bool    `json:"needOptimisation"`
+	TableName                  string               `json:"tableName"`
+	BillingMode                string               `json:"billingMode"`
+	ItemCount                  int64                `json:"itemCount"`
+	TableSizeMB                int64                `json:"tableSizeMB"`
+	ReadCapacityUnits          int64                `json:"readCapacityUnits"`
+	WriteCapacityUnits         int64                `json:"writeCapacityUnits"`
+	AvgConsumedRead            float64              `json:"avgConsumedRead"`
+	AvgConsumedWrite           float64              `json:"avgConsumedWrite"`
+	MetricsAvailable           bool                 `json:"metricsAvailable"`
+	UsageSeriesAvailable       bool                 `json:"usageSeriesAvailable"`
+	LookbackHours              int                  `json:"lookbackHours"`
+	TableArn                   *string              `json:"tableArn"`
+	EstimatedCost              string               `json:"estimatedCost"`
+	UtilizationPct             float64              `json:"utilizationPct"`
+	CurrentCost                float64              `json:"currentCost"`
+	ActualCost                 float64              `json:"actualCost"`
+	PotentialSavings           float64              `json:"potentialSavings"`
+	PotentialSavingsP          float64              `json:"potentialSavingsP"`
+	Recommendation             string               `json:"recommendation"`
+	NeedOptimisation           bool                 `json:"needOptimisation"`
+	GSIs                       []GSIInfo            `json:"gsis"`
+	TableClass                 string               `json:"tableClass"`
+	StandardClassCost          float64              `json:"standardClassCost"`
+	IAClassCost                float64              `json:"iaClassCost"`
+	StorageToThroughput        float64              `json:"storageToThroughput"`
+	BreakEvenRatio             float64              `json:"breakEvenRatio"`
+	TableClassRecommendation   string               `json:"tableClassRecommendation"`
+	CreationDateTime           *time.Time           `json:"creationDateTime"`
+	LastIncreaseDateTime       *time.Time           `json:"lastIncreaseDateTime"`
+	LastDecreaseDateTime       *time.Time           `json:"lastDecreaseDateTime"`
+	LastConsumedAt             *time.Time           `json:"lastConsumedAt"`
+	IsIdle                     bool                 `json:"isIdle"`
+	IdleAction                 string               `json:"idleAction"`
+	IdleReason                 string               `json:"idleReason"`
+	IdleMonthlyCostAvoided     float64              `json:"idleMonthlyCostAvoided"`
+	ReadThrottleEvents         float64              `json:"readThrottleEvents"`
+	WriteThrottleEvents        float64              `json:"writeThrottleEvents"`
+	ThrottledRequests          float64              `json:"throttledRequests"`
+	HotHoursPct                float64              `json:"hotHoursPct"`
+	IsThrottled                bool                 `json:"isThrottled"`
+	ThrottlingRecommendation   string               `json:"throttlingRecommendation"`
+	ThrottlingExtraCost        float64              `json:"throttlingExtraCost"`
+	PITREnabled                bool                 `json:"pitrEnabled"`
+	PITRMonthlyCost            float64              `json:"pitrMonthlyCost"`
+	Backups                    []BackupInfo         `json:"backups" csv:"-"`
+	BackupCount                int64                `json:"backupCount"`
+	BackupSizeMB               float64              `json:"backupSizeMB"`
+	BackupMonthlyCost          float64              `json:"backupMonthlyCost"`
+	StaleBackupCount           int64                `json:"staleBackupCount"`
+	BackupSavings              float64              `json:"backupSavings"`
+	BackupRecommendation       string               `json:"backupRecommendation"`
+	Replicas                   []ReplicaInfo        `json:"replicas"`
+	ReplicatedWriteMonthlyCost float64              `json:"replicatedWriteMonthlyCost"`
+	ReplicaSavings             float64              `json:"replicaSavings"`
+	ReplicaRecommendation      string               `json:"replicaRecommendation"`
+	StreamEnabled              bool                 `json:"streamEnabled"`
+	StreamViewType             string               `json:"streamViewType"`
+	StreamArn                  *string              `json:"streamArn"`
+	StreamReadRequests         float64              `json:"streamReadRequests"`
+	StreamMetricsAvailable     bool                 `json:"streamMetricsAvailable"`
+	StreamMonthlyCost          float64              `json:"streamMonthlyCost"`
+	KinesisDestinations        []string             `json:"kinesisDestinations"`
+	KinesisMonthlyCost         float64              `json:"kinesisMonthlyCost"`
+	StreamRecommendation       string               `json:"streamRecommendation"`
+	Simulation                 []SimulationResult   `json:"simulation"`
+	ReadAutoScaling            *AutoScalingSettings `json:"readAutoScaling"`
+	WriteAutoScaling           *AutoScalingSettings `json:"writeAutoScaling"`
+	AutoScalingAdvice          []AutoScalingAdvice  `json:"autoScalingAdvice"`
+	RecommendedRCU             int64                `json:"recommendedRCU"`
+	RecommendedWCU             int64                `json:"recommendedWCU"`
+	RightSizedCost             float64              `json:"rightSizedCost"`
+	OnDemandCost               float64              `json:"onDemandCost"`
+	SavingsVsCurrent           float64              `json:"savingsVsCurrent"`
+	SavingsVsOnDemand          float64              `json:"savingsVsOnDemand"`
+	ConsumedReadSeries         []float64            `json:"consumedReadSeries" csv:"-"`
+	ConsumedWriteSeries        []float64            `json:"consumedWriteSeries" csv:"-"`
+	ProvisionedReadSeries      []float64            `json:"provisionedReadSeries" csv:"-"`
+	ProvisionedWriteSeries     []float64            `json:"provisionedWriteSeries" csv:"-"`
+}
+
+// GSIInfo describes a global secondary index. GSIs carry their own
+// provisioned capacity and CloudWatch metrics, so they are analysed as
+// children of the table and rolled up into its totals.
+type GSIInfo struct {
+	IndexName              string               `json:"indexName"`
+	IndexArn               *string              `json:"indexArn"`
+	ItemCount              int64                `json:"itemCount"`
+	IndexSizeMB            int64                `json:"indexSizeMB"`
+	ReadCapacityUnits      int64                `json:"readCapacityUnits"`
+	WriteCapacityUnits     int64                `json:"writeCapacityUnits"`
+	AvgConsumedRead        float64              `json:"avgConsumedRead"`
+	AvgConsumedWrite       float64              `json:"avgConsumedWrite"`
+	MetricsAvailable       bool                 `json:"metricsAvailable"`
+	UsageSeriesAvailable   bool                 `json:"usageSeriesAvailable"`
+	EstimatedCost          float64              `json:"estimatedCost"`
+	UtilizationPct         float64              `json:"utilizationPct"`
+	CurrentCost            float64              `json:"currentCost"`
+	ActualCost             float64              `json:"actualCost"`
+	PotentialSavings       float64              `json:"potentialSavings"`
+	PotentialSavingsP      float64              `json:"potentialSavingsP"`
+	Recommendation         string               `json:"recommendation"`
+	NeedOptimisation       bool                 `json:"needOptimisation"`
+	ReadAutoScaling        *AutoScalingSettings `json:"readAutoScaling"`
+	WriteAutoScaling       *AutoScalingSettings `json:"writeAutoScaling"`
+	AutoScalingAdvice      []AutoScalingAdvice  `json:"autoScalingAdvice"`
+	RecommendedRCU         int64                `json:"recommendedRCU"`
+	RecommendedWCU         int64                `json:"recommendedWCU"`
+	RightSizedCost         float64              `json:"rightSizedCost"`
+	OnDemandCost           float64              `json:"onDemandCost"`
+	SavingsVsCurrent       float64              `json:"savingsVsCurrent"`
+	SavingsVsOnDemand      float64              `json:"savingsVsOnDemand"`
+	ReadThrottleEvents     float64              `json:"readThrottleEvents"`
+	WriteThrottleEvents    float64              `json:"writeThrottleEvents"`
+	HotHoursPct            float64              `json:"hotHoursPct"`
+	IsThrottled            bool                 `json:"isThrottled"`
+	ConsumedReadSeries     []float64            `json:"consumedReadSeries" csv:"-"`
+	ConsumedWriteSeries    []float64            `json:"consumedWriteSeries" csv:"-"`
+	ProvisionedReadSeries  []float64            `json:"provisionedReadSeries" csv:"-"`
+	ProvisionedWriteSeries []float64            `json:"provisionedWriteSeries" csv:"-"`
+}
+
+// ReplicaInfo is a global table replica in another region. Writes to the
+// table are replicated to, and billed in, every replica region.
+type ReplicaInfo struct {
+	RegionName                 string  `json:"regionName"`
+	ReplicaStatus              string  `json:"replicaStatus"`
+	ReadCapacityUnits          int64   `json:"readCapacityUnits"`
+	AvgConsumedRead            float64 `json:"avgConsumedRead"`
+	ReadRequestUnits           float64 `json:"readRequestUnits"`
+	MetricsAvailable           bool    `json:"metricsAvailable"`
+	ReplicatedWriteMonthlyCost float64 `json:"replicatedWriteMonthlyCost"`
+	MonthlyCost                float64 `json:"monthlyCost"`
+	RemovalCandidate           bool    `json:"removalCandidate"`
+	PotentialSavings           float64 `json:"potentialSavings"`
+}
+
+// SimulationResult is the cost of replaying a table's hourly traffic under
+// one capacity scenario, and the hours it would have been throttled.
+type SimulationResult struct {
+	Scenario       string  `json:"scenario"`
+	Cost           float64 `json:"cost"`
+	ThrottledHours int     `json:"throttledHours"`
 }
 
 func GetAvgMetric(ctx context.Context, cw *cloudwatch.Client, tableName, metric string, start, end time.Time) (float64, error) {
+	return getAvgDynamoDBMetric(ctx, cw, tableDimensions(tableName), metric, start, end)
+}
+
+// GetIndexAvgMetric is GetAvgMetric for a global secondary index.
+func GetIndexAvgMetric(ctx context.Context, cw *cloudwatch.Client, tableName, indexName, metric string, start, end time.Time) (float64, error) {
+	return getAvgDynamoDBMetric(ctx, cw, indexDimensions(tableName, indexName), metric, start, end)
+}
+
+// GetConsumedCapacitySeries returns hourly consumed capacity of a table, or of
+// a GSI when indexName is set, in units per second (hourly Sum / 3600).
+// DynamoDB emits no datapoints without traffic, so an empty window is a
+// series of zeros; only a failed call returns an error.
+func GetConsumedCapacitySeries(ctx context.Context, cw *cloudwatch.Client, tableName, indexName, metric string, start time.Time, hours int) ([]float64, error) {
+	series, err := GetHourlySeries(ctx, cw, "AWS/DynamoDB", metric, capacityDimensions(tableName, indexName), types.StatisticSum, start, hours)
+	if errors.Is(err, ErrNoDatapoints) {
+		return make([]float64, hours), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range series {
+		series[i] /= 3600
+	}
+	return ZeroFill(series), nil
+}
+
+// GetProvisionedCapacitySeries returns hourly provisioned capacity of a table
+// or GSI. It moves during the day when autoscaling is configured.
+func GetProvisionedCapacitySeries(ctx context.Context, cw *cloudwatch.Client, tableName, indexName, metric string, start time.Time, hours int) ([]float64, error) {
+	series, err := GetHourlySeries(ctx, cw, "AWS/DynamoDB", metric, capacityDimensions(tableName, indexName), types.StatisticAverage, start, hours)
+	if err != nil {
+		return nil, err
+	}
+	return ForwardFill(series), nil
+}
+
+func capacityDimensions(tableName, indexName string) []types.Dimension {
+	if indexName == "" {
+		return tableDimensions(tableName)
+	}
+	return indexDimensions(tableName, indexName)
+}
+
+func tableDimensions(tableName string) []types.Dimension {
+	return []types.Dimension{
+		{Name: aws.String("TableName"), Value: aws.String(tableName)},
+	}
+}
+
+func indexDimensions(tableName, indexName string) []types.Dimension {
+	return []types.Dimension{
+		{Name: aws.String("TableName"), Value: aws.String(tableName)},
+		{Name: aws.String("GlobalSecondaryIndexName"), Value: aws.String(indexName)},
+	}
+}
+
+func getAvgDynamoDBMetric(ctx context.Context, cw *cloudwatch.Client, dimensions []types.Dimension, metric string, start, end time.Time) (float64, error) {
 	out, err := cw.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
 		Namespace:  aws.String("AWS/DynamoDB"),
 		MetricName: aws.String(metric),
-		Dimensions: []types.Dimension{
-			{
-				Name:  aws.String("TableName"),
-				Value: aws.String(tableName),
-			},
-		},
-		StartTime: aws.Time(start),
-		EndTime:   aws.Time(end),
-		Period:    aws.Int32(3600), // hourly average
+		Dimensions: dimensions,
+		StartTime:  aws.Time(start),
+		EndTime:    aws.Time(end),
+		Period:     aws.Int32(3600), // hourly average
 		Statistics: []types.Statistic{
 			types.StatisticAverage,
 		},
@@ -63,6 +240,81 @@ func GetAvgMetric(ctx context.Context, cw *cloudwatch.Client, tableName, metric
 	return total / float64(len(out.Datapoints)), nil
 }
 
+// GetSumMetric totals a count metric, such as throttle events, of a table or
+// of a GSI when indexName is set. CloudWatch only emits these metrics when the
+// count is non-zero, so no datapoints means zero rather than an error.
+func GetSumMetric(ctx context.Context, cw *cloudwatch.Client, tableName, indexName, metric string, start, end time.Time) (float64, error) {
+	out, err := cw.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/DynamoDB"),
+		MetricName: aws.String(metric),
+		Dimensions: capacityDimensions(tableName, indexName),
+		StartTime:  aws.Time(start),
+		EndTime:    aws.Time(end),
+		Period:     aws.Int32(3600),
+		Statistics: []types.Statistic{
+			types.StatisticSum,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, dp := range out.Datapoints {
+		total += aws.ToFloat64(dp.Sum)
+	}
+	return total, nil
+}
+
+// throttledOperations are the operations DynamoDB publishes ThrottledRequests for.
+var throttledOperations = []string{
+	"GetItem", "PutItem", "UpdateItem", "DeleteItem", "Query", "Scan",
+	"BatchGetItem", "BatchWriteItem", "TransactGetItems", "TransactWriteItems",
+}
+
+// GetThrottledRequests totals ThrottledRequests of a table across operations.
+// The metric is only published per operation, so all of them are fetched in a
+// single GetMetricData call.
+func GetThrottledRequests(ctx context.Context, cw *cloudwatch.Client, tableName string, start, end time.Time) (float64, error) {
+	var queries []types.MetricDataQuery
+	for i, op := range throttledOperations {
+		queries = append(queries, types.MetricDataQuery{
+			Id: aws.String(fmt.Sprintf("op%d", i)),
+			MetricStat: &types.MetricStat{
+				Metric: &types.Metric{
+					Namespace:  aws.String("AWS/DynamoDB"),
+					MetricName: aws.String("ThrottledRequests"),
+					Dimensions: []types.Dimension{
+						{Name: aws.String("TableName"), Value: aws.String(tableName)},
+						{Name: aws.String("Operation"), Value: aws.String(op)},
+					},
+				},
+				Period: aws.Int32(3600),
+				Stat:   aws.String(string(types.StatisticSum)),
+			},
+		})
+	}
+
+	var total float64
+	paginator := cloudwatch.NewGetMetricDataPaginator(cw, &cloudwatch.GetMetricDataInput{
+		StartTime:         aws.Time(start),
+		EndTime:           aws.Time(end),
+		MetricDataQueries: queries,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("GetMetricData for ThrottledRequests failed: %w", err)
+		}
+		for _, result := range page.MetricDataResults {
+			for _, v := range result.Values {
+				total += v
+			}
+		}
+	}
+	return total, nil
+}
+
 func EstimateDynamoDBCost(readUnits, writeUnits float64, storageBytes int64, hours int, rcuPrice, wcuPrice, storagePricePerGBMonth float64) float64 {
 	// Default prices (us-east-1) if zero
 	if rcuPrice == 0 {
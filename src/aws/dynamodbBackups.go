@@ -0,0 +1,74 @@
+package awsclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// BackupInfo is an on-demand backup of a DynamoDB table.
+type BackupInfo struct {
+	BackupName  string     `json:"backupName"`
+	BackupArn   string     `json:"backupArn"`
+	BackupType  string     `json:"backupType"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	SizeMB      float64    `json:"sizeMB"`
+	MonthlyCost float64    `json:"monthlyCost"`
+	Stale       bool       `json:"stale"`
+	Redundant   bool       `json:"redundant"`
+}
+
+// GetPITREnabled reports whether point-in-time recovery is on for a table.
+func (c *AWSClient) GetPITREnabled(ctx context.Context, tableName string) (bool, error) {
+	out, err := c.DynamoDB.DescribeContinuousBackups(ctx, &dynamodb.DescribeContinuousBackupsInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	desc := out.ContinuousBackupsDescription
+	if desc == nil || desc.PointInTimeRecoveryDescription == nil {
+		return false, nil
+	}
+	return desc.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus == dynamotypes.PointInTimeRecoveryStatusEnabled, nil
+}
+
+// GetTableBackups lists all on-demand backups of a table, including those
+// created by AWS Backup.
+func (c *AWSClient) GetTableBackups(ctx context.Context, tableName string) ([]BackupInfo, error) {
+	var backups []BackupInfo
+	input := &dynamodb.ListBackupsInput{
+		TableName:  aws.String(tableName),
+		BackupType: dynamotypes.BackupTypeFilterAll,
+	}
+
+	for {
+		out, err := c.DynamoDB.ListBackups(ctx, input)
+		if err != nil {
+			return backups, err
+		}
+
+		for _, b := range out.BackupSummaries {
+			if b.BackupStatus == dynamotypes.BackupStatusDeleted {
+				continue
+			}
+			backups = append(backups, BackupInfo{
+				BackupName: aws.ToString(b.BackupName),
+				BackupArn:  aws.ToString(b.BackupArn),
+				BackupType: string(b.BackupType),
+				CreatedAt:  b.BackupCreationDateTime,
+				SizeMB:     float64(aws.ToInt64(b.BackupSizeBytes)) / 1024 / 1024,
+			})
+		}
+
+		if out.LastEvaluatedBackupArn == nil {
+			break
+		}
+		input.ExclusiveStartBackupArn = out.LastEvaluatedBackupArn
+	}
+	return backups, nil
+}
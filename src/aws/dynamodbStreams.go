@@ -0,0 +1,60 @@
+package awsclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// GetKinesisDestinations returns the ARNs of the active Kinesis Data Streams
+// destinations of a table.
+func (c *AWSClient) GetKinesisDestinations(ctx context.Context, tableName string) ([]string, error) {
+	out, err := c.DynamoDB.DescribeKinesisStreamingDestination(ctx, &dynamodb.DescribeKinesisStreamingDestinationInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var destinations []string
+	for _, d := range out.KinesisDataStreamDestinations {
+		if d.DestinationStatus == dynamotypes.DestinationStatusActive || d.DestinationStatus == dynamotypes.DestinationStatusEnabling {
+			destinations = append(destinations, aws.ToString(d.StreamArn))
+		}
+	}
+	return destinations, nil
+}
+
+// GetStreamReadRequests counts GetRecords calls made against a table's
+// DynamoDB stream, i.e. the sample count of their request latency.
+func GetStreamReadRequests(ctx context.Context, cw *cloudwatch.Client, tableName, streamLabel string, start, end time.Time) (float64, error) {
+	out, err := cw.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/DynamoDB"),
+		MetricName: aws.String("SuccessfulRequestLatency"),
+		Dimensions: []types.Dimension{
+			{Name: aws.String("TableName"), Value: aws.String(tableName)},
+			{Name: aws.String("StreamLabel"), Value: aws.String(streamLabel)},
+			{Name: aws.String("Operation"), Value: aws.String("GetRecords")},
+		},
+		StartTime: aws.Time(start),
+		EndTime:   aws.Time(end),
+		Period:    aws.Int32(3600),
+		Statistics: []types.Statistic{
+			types.StatisticSampleCount,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, dp := range out.Datapoints {
+		total += aws.ToFloat64(dp.SampleCount)
+	}
+	return total, nil
+}
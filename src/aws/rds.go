@@ -3,34 +3,163 @@ package awsclient
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 )
 
+// RDSInfo is an RDS DB instance with the metrics and cost estimate the
+// scanner collects for it. Aurora instances carry their cluster id.
 type RDSInfo struct {
-	TableName          string  `json:"tableName"`
-	BillingMode        string  `json:"billingMode"`
-	InstanceType       string  `json:"instanceType"`
-	Connections        float64 `json:"connections"`
-	ItemCount          int64   `json:"itemCount"`
-	TableSizeMB        float64 `json:"tableSizeMB"`
-	ReadCapacityUnits  int64   `json:"readCapacityUnits"`
-	WriteCapacityUnits int64   `json:"writeCapacityUnits"`
-	AvgConsumedRead    float64 `json:"avgConsumedRead"`
-	AvgConsumedWrite   float64 `json:"avgConsumedWrite"`
-	MetricsAvailable   bool    `json:"metricsAvailable"`
-	TableArn           *string `json:"tableArn"`
-	EstimatedCost      float64 `json:"estimatedCost"`
-	UtilizationPct     float64 `json:"utilizationPct"`
-	CurrentCost        float64 `json:"currentCost"`
-	ActualCost         float64 `json:"actualCost"`
-	PotentialSavings   float64 `json:"potentialSavings"`
-	PotentialSavingsP  float64 `json:"potentialSavingsP"`
-	Recommendation     string  `json:"recommendation"`
-	NeedOptimisation   bool    `json:"needOptimisation"`
+	InstanceID                     string            `json:"instanceId"`
+	Region                         string            `json:"region"`
+	InstanceArn                    *string           `json:"instanceArn"`
+	Status                         string            `json:"status"`
+	CreateTime                     *time.Time        `json:"createTime"`
+	Engine                         string            `json:"engine"`
+	EngineVersion                  string            `json:"engineVersion"`
+	LicenseModel                   string            `json:"licenseModel"`
+	InstanceClass                  string            `json:"instanceClass"`
+	AllocatedStorageGB             int64             `json:"allocatedStorageGB"`
+	UsedStorageGB                  float64           `json:"usedStorageGB"`
+	FreeStorageGB                  float64           `json:"freeStorageGB"`
+	FreeStoragePct                 float64           `json:"freeStoragePct"`
+	MaxAllocatedStorageGB          int64             `json:"maxAllocatedStorageGB"`
+	StorageAutoscaling             bool              `json:"storageAutoscaling"`
+	StorageGrowthGBPerDay          float64           `json:"storageGrowthGBPerDay"`
+	DaysUntilFull                  float64           `json:"daysUntilFull"` // -1 without a free storage series or growth
+	RecommendedVolumeGB            int64             `json:"recommendedVolumeGB"`
+	StorageRightSizingSavings      float64           `json:"storageRightSizingSavings"`
+	StorageRecommendation          string            `json:"storageRecommendation"`
+	IsIdle                         bool              `json:"isIdle"`
+	IdleAction                     string            `json:"idleAction"`
+	IdleReason                     string            `json:"idleReason"`
+	IdleMonthlyCostAvoided         float64           `json:"idleMonthlyCostAvoided"`
+	AvgACU                         float64           `json:"avgACU"`
+	PeakACU                        float64           `json:"peakACU"`
+	MinACU                         float64           `json:"minACU"`
+	MaxACU                         float64           `json:"maxACU"`
+	RecommendedMinACU              float64           `json:"recommendedMinACU"`
+	RecommendedMaxACU              float64           `json:"recommendedMaxACU"`
+	ServerlessMonthlyCost          float64           `json:"serverlessMonthlyCost"`
+	ServerlessSavings              float64           `json:"serverlessSavings"`
+	ServerlessRecommendation       string            `json:"serverlessRecommendation"`
+	P95ReplicaLagSeconds           float64           `json:"p95ReplicaLagSeconds"`
+	ReplicasMonthlyCost            float64           `json:"replicasMonthlyCost"`
+	IsUnderusedReplica             bool              `json:"isUnderusedReplica"`
+	ReplicaAction                  string            `json:"replicaAction"`
+	ReplicaSavings                 float64           `json:"replicaSavings"`
+	ReplicaRecommendation          string            `json:"replicaRecommendation"`
+	IsNonProd                      bool              `json:"isNonProd"`
+	MultiAZSavings                 float64           `json:"multiAZSavings"`
+	MultiAZRecommendation          string            `json:"multiAZRecommendation"`
+	StandardSupportEnd             *time.Time        `json:"standardSupportEnd"`
+	ExtendedSupportStatus          string            `json:"extendedSupportStatus"`
+	ExtendedSupportMonthlyCost     float64           `json:"extendedSupportMonthlyCost"`
+	ExtendedSupportAnnualExposure  float64           `json:"extendedSupportAnnualExposure"`
+	ExtendedSupportRecommendation  string            `json:"extendedSupportRecommendation"`
+	BackupRetentionDays            int32             `json:"backupRetentionDays"`
+	SnapshotCount                  int               `json:"snapshotCount"`
+	SnapshotMonthlyCost            float64           `json:"snapshotMonthlyCost"`
+	BackupRetentionSavings         float64           `json:"backupRetentionSavings"`
+	BackupRetentionRecommendation  string            `json:"backupRetentionRecommendation"`
+	StorageType                    string            `json:"storageType"`
+	ProvisionedIOPS                int64             `json:"provisionedIOPS"`
+	StorageThroughput              int64             `json:"storageThroughput"`
+	MultiAZ                        bool              `json:"multiAZ"`
+	Deployment                     string            `json:"deployment"`
+	ReadReplicaSourceID            string            `json:"readReplicaSourceId"`
+	ReadReplicaIDs                 []string          `json:"readReplicaIds"`
+	ClusterID                      string            `json:"clusterId"`
+	ClusterRole                    string            `json:"clusterRole"`
+	Tags                           map[string]string `json:"tags"`
+	AvgCPU                         float64           `json:"avgCPU"`
+	AvgConnections                 float64           `json:"avgConnections"`
+	AvgReadIOPS                    float64           `json:"avgReadIOPS"`
+	AvgWriteIOPS                   float64           `json:"avgWriteIOPS"`
+	MetricsAvailable               bool              `json:"metricsAvailable"`
+	P95CPU                         float64           `json:"p95CPU"`
+	MinFreeableMemoryGB            float64           `json:"minFreeableMemoryGB"`
+	PeakConnections                float64           `json:"peakConnections"`
+	P95NetworkMBps                 float64           `json:"p95NetworkMBps"`
+	RecommendedClass               string            `json:"recommendedClass"`
+	ProjectedCPU                   float64           `json:"projectedCPU"`
+	ProjectedMemoryPct             float64           `json:"projectedMemoryPct"`
+	ProjectedConnectionsPct        float64           `json:"projectedConnectionsPct"`
+	RightSizingSavings             float64           `json:"rightSizingSavings"`
+	RightSizingRecommendation      string            `json:"rightSizingRecommendation"`
+	GravitonClass                  string            `json:"gravitonClass"`
+	GravitonSavings                float64           `json:"gravitonSavings"`
+	GravitonNeedsUpgrade           bool              `json:"gravitonNeedsUpgrade"`
+	GravitonMinEngineVersion       string            `json:"gravitonMinEngineVersion"`
+	GravitonRecommendation         string            `json:"gravitonRecommendation"`
+	P99IOPS                        float64           `json:"p99IOPS"`
+	P99ThroughputMBps              float64           `json:"p99ThroughputMBps"`
+	RecommendedStorageType         string            `json:"recommendedStorageType"`
+	RecommendedStorageGB           int64             `json:"recommendedStorageGB"`
+	RecommendedIOPS                int64             `json:"recommendedIOPS"`
+	RecommendedThroughput          int64             `json:"recommendedThroughput"`
+	StorageMigrationDelta          float64           `json:"storageMigrationDelta"`
+	StorageMigrationRecommendation string            `json:"storageMigrationRecommendation"`
+	BackupStorageBilledGB          float64           `json:"backupStorageBilledGB"`
+	ComputeMonthlyCost             float64           `json:"computeMonthlyCost"`
+	StorageMonthlyCost             float64           `json:"storageMonthlyCost"`
+	IOPSMonthlyCost                float64           `json:"iopsMonthlyCost"`
+	ThroughputMonthlyCost          float64           `json:"throughputMonthlyCost"`
+	BackupMonthlyCost              float64           `json:"backupMonthlyCost"`
+	EstimatedCost                  float64           `json:"estimatedCost"`
+	PricingSource                  string            `json:"pricingSource"`
+	PotentialSavings               float64           `json:"potentialSavings"`
+	Recommendation                 string            `json:"recommendation"`
+	NeedOptimisation               bool              `json:"needOptimisation"`
+
+	CPUSeries               []float64 `json:"cpuSeries,omitempty" csv:"-"`
+	FreeableMemorySeries    []float64 `json:"freeableMemorySeries,omitempty" csv:"-"`
+	ConnectionsSeries       []float64 `json:"connectionsSeries,omitempty" csv:"-"`
+	NetworkThroughputSeries []float64 `json:"networkThroughputSeries,omitempty" csv:"-"`
+	IOPSSeries              []float64 `json:"iopsSeries,omitempty" csv:"-"`
+	ThroughputSeries        []float64 `json:"throughputSeries,omitempty" csv:"-"`
+	FreeStorageSeries       []float64 `json:"freeStorageSeries,omitempty" csv:"-"`
+	ACUSeries               []float64 `json:"acuSeries,omitempty" csv:"-"`
+	ACUUtilizationSeries    []float64 `json:"acuUtilizationSeries,omitempty" csv:"-"`
+	ReplicaLagSeries        []float64 `json:"replicaLagSeries,omitempty" csv:"-"`
+}
+
+// NewRDSInfo maps the DescribeDBInstances description of an instance onto
+// RDSInfo. Metrics and cost are filled in by the caller.
+func NewRDSInfo(inst rdstypes.DBInstance) RDSInfo {
+	info := RDSInfo{
+		InstanceID:            aws.ToString(inst.DBInstanceIdentifier),
+		InstanceArn:           inst.DBInstanceArn,
+		Status:                aws.ToString(inst.DBInstanceStatus),
+		CreateTime:            inst.InstanceCreateTime,
+		Engine:                aws.ToString(inst.Engine),
+		EngineVersion:         aws.ToString(inst.EngineVersion),
+		LicenseModel:          aws.ToString(inst.LicenseModel),
+		InstanceClass:         aws.ToString(inst.DBInstanceClass),
+		AllocatedStorageGB:    int64(aws.ToInt32(inst.AllocatedStorage)),
+		MaxAllocatedStorageGB: int64(aws.ToInt32(inst.MaxAllocatedStorage)),
+		StorageType:           aws.ToString(inst.StorageType),
+		ProvisionedIOPS:       int64(aws.ToInt32(inst.Iops)),
+		StorageThroughput:     int64(aws.ToInt32(inst.StorageThroughput)),
+		MultiAZ:               aws.ToBool(inst.MultiAZ),
+		BackupRetentionDays:   aws.ToInt32(inst.BackupRetentionPeriod),
+		ReadReplicaSourceID:   aws.ToString(inst.ReadReplicaSourceDBInstanceIdentifier),
+		ReadReplicaIDs:        inst.ReadReplicaDBInstanceIdentifiers,
+		ClusterID:             aws.ToString(inst.DBClusterIdentifier),
+		Tags:                  map[string]string{},
+	}
+	for _, tag := range inst.TagList {
+		info.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	info.Deployment = RDSDeployment(info)
+	info.StorageAutoscaling = info.MaxAllocatedStorageGB > info.AllocatedStorageGB
+	return info
 }
 
 func GetRDSMetric(ctx context.Context, cw *cloudwatch.Client, instanceID, metricName string, start, end time.Time) (float64, error) {
@@ -63,15 +192,24 @@ func GetRDSMetric(ctx context.Context, cw *cloudwatch.Client, instanceID, metric
 	return total / float64(len(out.Datapoints)), nil
 }
 
-func RecommendRDS(cpu float64, freeStorage float64) string {
-	if cpu < 10 {
-		return "Consider downsizing or using Aurora Serverless"
-	}
-	if cpu > 80 {
-		return "Consider upgrading instance class"
-	}
-	if freeStorage < 1024*1024*1024*10 { // less than 10 GB
-		return "Low storage: increase allocated storage"
+// GetRDSSeries returns the hourly series of an instance metric; see
+// GetHourlySeries for how missing hours are reported.
+func GetRDSSeries(ctx context.Context, cw *cloudwatch.Client, instanceID, metric string, stat types.Statistic, start time.Time, hours int) ([]float64, error) {
+	return GetHourlySeries(ctx, cw, "AWS/RDS", metric, []types.Dimension{
+		{Name: aws.String("DBInstanceIdentifier"), Value: aws.String(instanceID)},
+	}, stat, start, hours)
+}
+
+// RDSMaxConnections is the default max_connections parameter of an engine on
+// an instance with the given memory.
+func RDSMaxConnections(engine string, memoryGiB float64) float64 {
+	bytes := memoryGiB * 1024 * 1024 * 1024
+	switch {
+	case strings.Contains(engine, "postgres"):
+		return math.Min(bytes/9531392, 5000)
+	case strings.Contains(engine, "mysql"), engine == "mariadb":
+		return bytes / 12582880
+	default:
+		return math.Inf(1) // not limited by a memory formula
 	}
-	return "Configuration OK"
 }
@@ -0,0 +1,104 @@
+package awsclient
+
+import (
+	"strings"
+	"time"
+)
+
+// EngineLifecycle is the support calendar of an engine major version.
+// Instances still on it after StandardSupportEnd are enrolled in RDS
+// Extended Support and billed per vCPU-hour until ExtendedSupportEnd.
+type EngineLifecycle struct {
+	Engine             string    `json:"engine"`
+	MajorVersion       string    `json:"majorVersion"`
+	StandardSupportEnd time.Time `json:"standardSupportEnd"`
+	ExtendedSupportEnd time.Time `json:"extendedSupportEnd"`
+	UpgradeTo          string    `json:"upgradeTo"`
+}
+
+// Extended Support list prices, $ per vCPU-hour (us-east-1). Year 3 starts
+// two years after the end of standard support.
+const (
+	extendedSupportYear1Price float64 = 0.100
+	extendedSupportYear3Price float64 = 0.200
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// rdsLifecycles is the bundled engine lifecycle calendar. Update it when AWS
+// publishes new end-of-support dates.
+var rdsLifecycles = []EngineLifecycle{
+	{"mysql", "5.7", date(2024, 2, 29), date(2027, 2, 28), "8.4"},
+	{"mysql", "8.0", date(2026, 7, 31), date(2029, 7, 31), "8.4"},
+	{"mysql", "8.4", date(2029, 7, 31), date(2032, 7, 31), "8.4"},
+	{"postgres", "11", date(2024, 2, 29), date(2027, 3, 31), "17"},
+	{"postgres", "12", date(2025, 2, 28), date(2028, 2, 29), "17"},
+	{"postgres", "13", date(2026, 2, 28), date(2029, 2, 28), "17"},
+	{"postgres", "14", date(2027, 2, 28), date(2030, 2, 28), "17"},
+	{"postgres", "15", date(2028, 2, 29), date(2031, 2, 28), "17"},
+	{"postgres", "16", date(2029, 2, 28), date(2032, 2, 29), "17"},
+	{"postgres", "17", date(2030, 2, 28), date(2033, 2, 28), "17"},
+	{"aurora-mysql", "2", date(2024, 10, 31), date(2027, 2, 28), "3"},
+	{"aurora-mysql", "3", date(2028, 4, 30), date(2031, 4, 30), "3"},
+	{"aurora-postgresql", "11", date(2024, 2, 29), date(2027, 3, 31), "16"},
+	{"aurora-postgresql", "12", date(2025, 2, 28), date(2028, 2, 29), "16"},
+	{"aurora-postgresql", "13", date(2026, 2, 28), date(2029, 2, 28), "16"},
+	{"aurora-postgresql", "14", date(2027, 2, 28), date(2030, 2, 28), "16"},
+	{"aurora-postgresql", "15", date(2028, 2, 29), date(2031, 2, 28), "16"},
+	{"aurora-postgresql", "16", date(2029, 2, 28), date(2032, 2, 29), "16"},
+}
+
+// EngineMajorVersion returns the major version used by the lifecycle
+// calendar: 8.0 for MySQL 8.0.35, 13 for PostgreSQL 13.12 and 2 for Aurora
+// MySQL 5.7.mysql_aurora.2.11.2.
+func EngineMajorVersion(engine, version string) string {
+	parts := strings.Split(version, ".")
+	switch {
+	case engine == "aurora-mysql":
+		for i, p := range parts {
+			if p == "mysql_aurora" && i+1 < len(parts) {
+				return parts[i+1]
+			}
+		}
+		if strings.HasPrefix(version, "5.6") {
+			return "1"
+		}
+		return ""
+	case engine == "mysql" || engine == "mariadb":
+		if len(parts) >= 2 {
+			return parts[0] + "." + parts[1]
+		}
+	}
+	return parts[0]
+}
+
+// GetEngineLifecycle returns the support calendar of an engine version, or
+// false if it is not in the bundled calendar.
+func GetEngineLifecycle(engine, version string) (EngineLifecycle, bool) {
+	major := EngineMajorVersion(engine, version)
+	for _, l := range rdsLifecycles {
+		if l.Engine == engine && l.MajorVersion == major {
+			return l, true
+		}
+	}
+	return EngineLifecycle{}, false
+}
+
+// ExtendedSupportRate is the Extended Support $ per vCPU-hour an engine
+// version is billed at a point in time, 0 while in standard support or after
+// Extended Support ends.
+func ExtendedSupportRate(l EngineLifecycle, at time.Time, region string) float64 {
+	if at.Before(l.StandardSupportEnd) || !at.Before(l.ExtendedSupportEnd) {
+		return 0
+	}
+	rate := extendedSupportYear1Price
+	if !at.Before(l.StandardSupportEnd.AddDate(2, 0, 0)) {
+		rate = extendedSupportYear3Price
+	}
+	if f, ok := rdsRegionFactor[region]; ok {
+		rate *= f
+	}
+	return rate
+}
@@ -0,0 +1,64 @@
+package awsclient
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEngineMajorVersion(t *testing.T) {
+	tests := []struct {
+		engine, version string
+		want            string
+	}{
+		{"mysql", "8.0.35", "8.0"},
+		{"mysql", "5.7.44-rds.20240408", "5.7"},
+		{"mariadb", "10.6.16", "10.6"},
+		{"postgres", "13.12", "13"},
+		{"postgres", "16", "16"},
+		{"aurora-postgresql", "14.9", "14"},
+		{"aurora-mysql", "5.7.mysql_aurora.2.11.2", "2"},
+		{"aurora-mysql", "8.0.mysql_aurora.3.05.2", "3"},
+		{"aurora-mysql", "5.6.10a", "1"},
+		{"aurora-mysql", "8.0", ""},
+	}
+	for _, tt := range tests {
+		if got := EngineMajorVersion(tt.engine, tt.version); got != tt.want {
+			t.Errorf("EngineMajorVersion(%q, %q) = %q, want %q", tt.engine, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestExtendedSupportRate(t *testing.T) {
+	l := EngineLifecycle{
+		Engine:             "postgres",
+		MajorVersion:       "12",
+		StandardSupportEnd: date(2025, 2, 28),
+		ExtendedSupportEnd: date(2028, 2, 29),
+	}
+	tests := []struct {
+		name   string
+		at     string
+		region string
+		want   float64
+	}{
+		{"standard support", "2025-02-27", "us-east-1", 0},
+		{"first day of extended support", "2025-02-28", "us-east-1", extendedSupportYear1Price},
+		{"year 2", "2026-06-01", "us-east-1", extendedSupportYear1Price},
+		{"year 3", "2027-02-28", "us-east-1", extendedSupportYear3Price},
+		{"extended support ended", "2028-02-29", "us-east-1", 0},
+		{"regional price", "2025-06-01", "eu-west-1", extendedSupportYear1Price * 1.10},
+		{"unknown region", "2025-06-01", "xx-nowhere-1", extendedSupportYear1Price},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.DateOnly, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ExtendedSupportRate(l, at, tt.region); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ExtendedSupportRate(%s, %s) = %v, want %v", tt.at, tt.region, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,494 @@
+package awsclient
+
+import (
+	"math"
+	"strings"
+)
+
+// RDS on-demand list prices. Instance prices are us-east-1 Single-AZ rates
+// for open-source engines; other engines, licenses, deployments and regions
+// are derived from them with the adjustments below. Update the catalog when
+// AWS changes its price list.
+
+const hoursPerMonth = 720
+
+// RDSInstanceSpec is the hardware of a DB instance class.
+type RDSInstanceSpec struct {
+	Class       string  `json:"class"`
+	Family      string  `json:"family"`
+	Size        string  `json:"size"`
+	VCPU        int     `json:"vcpu"`
+	MemoryGiB   float64 `json:"memoryGiB"`
+	NetworkGbps float64 `json:"networkGbps"`
+	Graviton    bool    `json:"graviton"`
+}
+
+// rdsFamily describes an instance family. Non-burstable families scale
+// linearly with vCPUs from the price of their large size.
+type rdsFamily struct {
+	memoryPerVCPU float64
+	graviton      bool
+	largePrice    float64 // $/hour for .large, RDS MySQL
+	auroraPrice   float64 // $/hour for .large, Aurora; 0 if not offered
+}
+
+var rdsFamilies = map[string]rdsFamily{
+	"m4":     {4, false, 0.175, 0},
+	"m5":     {4, false, 0.171, 0},
+	"m5d":    {4, false, 0.201, 0},
+	"m6i":    {4, false, 0.171, 0},
+	"m6id":   {4, false, 0.213, 0},
+	"m6in":   {4, false, 0.240, 0},
+	"m6idn":  {4, false, 0.268, 0},
+	"m6g":    {4, true, 0.152, 0},
+	"m6gd":   {4, true, 0.178, 0},
+	"m7i":    {4, false, 0.180, 0},
+	"m7g":    {4, true, 0.168, 0},
+	"m8g":    {4, true, 0.176, 0},
+	"r4":     {7.625, false, 0.240, 0.290},
+	"r5":     {8, false, 0.240, 0.290},
+	"r5b":    {8, false, 0.298, 0},
+	"r5d":    {8, false, 0.288, 0},
+	"r6i":    {8, false, 0.240, 0.290},
+	"r6id":   {8, false, 0.301, 0},
+	"r6in":   {8, false, 0.349, 0},
+	"r6idn":  {8, false, 0.391, 0},
+	"r6g":    {8, true, 0.215, 0.260},
+	"r6gd":   {8, true, 0.259, 0},
+	"r7i":    {8, false, 0.252, 0.304},
+	"r7g":    {8, true, 0.239, 0.276},
+	"r8g":    {8, true, 0.251, 0.290},
+	"x1":     {15.25, false, 0.209, 0},
+	"x1e":    {30.5, false, 0.417, 0},
+	"x2g":    {16, true, 0.334, 0.400},
+	"x2idn":  {16, false, 0.313, 0.375},
+	"x2iedn": {32, false, 0.667, 0.800},
+	"x2iezn": {32, false, 0.834, 0},
+	"z1d":    {8, false, 0.298, 0},
+}
+
+// smallest size of families that do not start at large
+var rdsFamilyMinSize = map[string]string{
+	"x1":     "16xlarge",
+	"x1e":    "xlarge",
+	"x2idn":  "16xlarge",
+	"x2iedn": "xlarge",
+	"x2iezn": "2xlarge",
+}
+
+// Burstable classes are priced per size.
+var rdsBurstable = map[string]map[string]struct {
+	price       float64
+	auroraPrice float64
+	vcpu        int
+	memoryGiB   float64
+}{
+	"t2": {
+		"micro": {0.017, 0, 1, 1}, "small": {0.034, 0, 1, 2}, "medium": {0.068, 0, 2, 4},
+		"large": {0.136, 0, 2, 8}, "xlarge": {0.272, 0, 4, 16}, "2xlarge": {0.544, 0, 8, 32},
+	},
+	"t3": {
+		"micro": {0.017, 0, 2, 1}, "small": {0.034, 0.041, 2, 2}, "medium": {0.068, 0.082, 2, 4},
+		"large": {0.136, 0.164, 2, 8}, "xlarge": {0.272, 0, 4, 16}, "2xlarge": {0.544, 0, 8, 32},
+	},
+	"t4g": {
+		"micro": {0.016, 0, 2, 1}, "small": {0.032, 0, 2, 2}, "medium": {0.065, 0.073, 2, 4},
+		"large": {0.129, 0.146, 2, 8}, "xlarge": {0.258, 0, 4, 16}, "2xlarge": {0.517, 0, 8, 32},
+	},
+}
+
+var rdsSizeVCPU = map[string]int{
+	"large": 2, "xlarge": 4, "2xlarge": 8, "4xlarge": 16, "8xlarge": 32, "12xlarge": 48,
+	"16xlarge": 64, "24xlarge": 96, "32xlarge": 128, "48xlarge": 192,
+}
+
+// RDSSizes lists non-burstable sizes from small to large.
+var RDSSizes = []string{"large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge", "24xlarge", "32xlarge", "48xlarge"}
+
+// engine price multipliers relative to RDS MySQL; engines not listed are
+// priced like MySQL (Oracle and Db2 BYOL, SQL Server before license)
+var rdsEngineFactor = map[string]float64{
+	"postgres": 1.05,
+}
+
+// license-included surcharge, $ per vCPU-hour
+var rdsLicensePerVCPU = map[string]float64{
+	"oracle-se2":     0.150,
+	"oracle-se2-cdb": 0.150,
+	"sqlserver-ex":   0.010,
+	"sqlserver-web":  0.045,
+	"sqlserver-se":   0.400,
+	"sqlserver-ee":   0.750,
+	"db2-se":         0.300,
+	"db2-ae":         0.600,
+}
+
+// regional price multipliers relative to us-east-1
+var rdsRegionFactor = map[string]float64{
+	"us-east-1":      1.0,
+	"us-east-2":      1.0,
+	"us-west-2":      1.0,
+	"us-west-1":      1.12,
+	"ca-central-1":   1.10,
+	"eu-west-1":      1.10,
+	"eu-west-2":      1.16,
+	"eu-west-3":      1.16,
+	"eu-central-1":   1.19,
+	"eu-north-1":     1.06,
+	"eu-south-1":     1.17,
+	"ap-south-1":     1.07,
+	"ap-southeast-1": 1.22,
+	"ap-southeast-2": 1.23,
+	"ap-northeast-1": 1.23,
+	"ap-northeast-2": 1.19,
+	"ap-northeast-3": 1.23,
+	"sa-east-1":      1.58,
+	"me-south-1":     1.21,
+	"af-south-1":     1.29,
+}
+
+// storage prices, $ per GB-month (us-east-1)
+var rdsStoragePrice = map[string]float64{
+	"gp2":          0.115,
+	"gp3":          0.115,
+	"io1":          0.125,
+	"io2":          0.125,
+	"standard":     0.10,
+	"aurora":       0.10,
+	"aurora-iopt1": 0.225,
+}
+
+const (
+	rdsProvisionedIOPSPrice float64 = 0.10  // $ per IOPS-month, io1/io2
+	rdsGP3IOPSPrice         float64 = 0.02  // $ per IOPS-month above the gp3 baseline
+	rdsGP3ThroughputPrice   float64 = 0.08  // $ per MiB/s-month above the gp3 baseline
+	rdsMagneticIOPrice      float64 = 0.10  // $ per million I/O requests, magnetic
+	rdsBackupPrice          float64 = 0.095 // $ per GB-month beyond the free allowance
+	auroraIOPrice           float64 = 0.20  // $ per million I/O requests, Aurora Standard
+	auroraACUPrice          float64 = 0.12  // $ per ACU-hour, Aurora Serverless v2 Standard
+	rdsDefaultHourlyRate    float64 = 0.10  // classes missing from the catalog
+	AuroraIOOptimizedFactor float64 = 1.3   // Aurora I/O-Optimized instance surcharge
+)
+
+// ServerlessClass is the instance class of Aurora Serverless v2 instances.
+const ServerlessClass = "db.serverless"
+
+// Deployment options of an RDS instance.
+const (
+	DeploymentSingleAZ       = "Single-AZ"
+	DeploymentMultiAZ        = "Multi-AZ"
+	DeploymentMultiAZCluster = "Multi-AZ cluster"
+	DeploymentAurora         = "Aurora"
+)
+
+// Pricing sources of a cost estimate. Anything but PricingCatalog is a guess
+// and should be treated as such.
+const (
+	PricingCatalog       = "catalog"
+	PricingDefaultRate   = "default rate"    // class not in the catalog
+	PricingUSEast1Prices = "us-east-1 rates" // region not in the catalog
+)
+
+// RDSCostBreakdown is the monthly cost of an RDS instance by line item.
+type RDSCostBreakdown struct {
+	Compute    float64 `json:"compute"`
+	Storage    float64 `json:"storage"`
+	IOPS       float64 `json:"iops"`
+	Throughput float64 `json:"throughput"`
+	Backup     float64 `json:"backup"`
+	Total      float64 `json:"total"`
+	// PricingSource lists what the estimate fell back on, joined by "; "
+	PricingSource string `json:"pricingSource"`
+}
+
+// RDSFamilyClasses lists the classes of a family in the catalog from the
+// smallest to the largest.
+func RDSFamilyClasses(family string) []string {
+	sizes := RDSSizes
+	if burstable, ok := rdsBurstable[family]; ok {
+		sizes = nil
+		for _, size := range []string{"micro", "small", "medium", "large", "xlarge", "2xlarge"} {
+			if _, ok := burstable[size]; ok {
+				sizes = append(sizes, size)
+			}
+		}
+	} else if _, ok := rdsFamilies[family]; !ok {
+		return nil
+	}
+
+	if minSize, ok := rdsFamilyMinSize[family]; ok {
+		for i, size := range sizes {
+			if size == minSize {
+				sizes = sizes[i:]
+				break
+			}
+		}
+	}
+
+	classes := make([]string, 0, len(sizes))
+	for _, size := range sizes {
+		classes = append(classes, "db."+family+"."+size)
+	}
+	return classes
+}
+
+// ParseRDSClass splits a class such as db.r6g.2xlarge into family and size.
+func ParseRDSClass(class string) (family, size string, ok bool) {
+	parts := strings.Split(class, ".")
+	if len(parts) != 3 || parts[0] != "db" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// GetRDSInstanceSpec returns the vCPU, memory and network of a DB instance
+// class, or false if the class is not in the catalog.
+func GetRDSInstanceSpec(class string) (RDSInstanceSpec, bool) {
+	family, size, ok := ParseRDSClass(class)
+	if !ok {
+		return RDSInstanceSpec{}, false
+	}
+
+	spec := RDSInstanceSpec{Class: class, Family: family, Size: size}
+	if sizes, ok := rdsBurstable[family]; ok {
+		s, ok := sizes[size]
+		if !ok {
+			return RDSInstanceSpec{}, false
+		}
+		spec.VCPU = s.vcpu
+		spec.MemoryGiB = s.memoryGiB
+		spec.Graviton = family == "t4g"
+	} else {
+		f, ok := rdsFamilies[family]
+		vcpu, sizeOK := rdsSizeVCPU[size]
+		if !ok || !sizeOK {
+			return RDSInstanceSpec{}, false
+		}
+		spec.VCPU = vcpu
+		spec.MemoryGiB = float64(vcpu) * f.memoryPerVCPU
+		spec.Graviton = f.graviton
+	}
+
+	// baseline bandwidth grows roughly linearly with size
+	spec.NetworkGbps = math.Max(0.5, float64(spec.VCPU)*0.3125)
+	return spec, true
+}
+
+// RDSHourlyRate returns the Single-AZ on-demand $/hour of a DB instance class
+// for an engine, license model and region. The bool is false when the class
+// is not in the catalog and a default rate was used.
+func RDSHourlyRate(class, engine, licenseModel, region string) (float64, bool) {
+	family, size, _ := ParseRDSClass(class)
+	aurora := IsAurora(engine)
+
+	var rate float64
+	if sizes, ok := rdsBurstable[family]; ok {
+		s := sizes[size]
+		rate = s.price
+		if aurora {
+			rate = s.auroraPrice
+		}
+	} else if f, ok := rdsFamilies[family]; ok {
+		perLarge := f.largePrice
+		if aurora {
+			perLarge = f.auroraPrice
+		}
+		rate = perLarge * float64(rdsSizeVCPU[size]) / 2
+	}
+
+	found := rate > 0
+	if !found {
+		rate = rdsDefaultHourlyRate
+	}
+
+	if f, ok := rdsEngineFactor[engine]; ok {
+		rate *= f
+	}
+
+	if licenseModel == "license-included" {
+		if spec, ok := GetRDSInstanceSpec(class); ok {
+			rate += rdsLicensePerVCPU[engine] * float64(spec.VCPU)
+		}
+	}
+
+	factor, _ := regionPriceFactor(region)
+	return rate * factor, found
+}
+
+// regionPriceFactor is the price multiplier of a region. Regions missing from
+// the catalog are priced like us-east-1 and return false.
+func regionPriceFactor(region string) (float64, bool) {
+	if f, ok := rdsRegionFactor[region]; ok {
+		return f, true
+	}
+	return 1, false
+}
+
+// IsAurora reports whether an engine is Aurora, whose storage and I/O are
+// billed at cluster level.
+func IsAurora(engine string) bool {
+	return strings.HasPrefix(engine, "aurora")
+}
+
+// RDSDeployment returns the deployment option of an instance.
+func RDSDeployment(info RDSInfo) string {
+	switch {
+	case IsAurora(info.Engine):
+		return DeploymentAurora
+	case info.ClusterID != "":
+		return DeploymentMultiAZCluster
+	case info.MultiAZ:
+		return DeploymentMultiAZ
+	default:
+		return DeploymentSingleAZ
+	}
+}
+
+// EstimateRDSCost prices an instance from the catalog. Multi-AZ runs a
+// standby, so compute, storage, IOPS and throughput are doubled. Members of a
+// Multi-AZ DB cluster, Aurora instances and read replicas are separate
+// instances and are priced one by one. Aurora storage and I/O are billed on the
+// cluster, so only compute is charged here.
+func EstimateRDSCost(info RDSInfo, region string) RDSCostBreakdown {
+	var cost RDSCostBreakdown
+	var sources []string
+
+	if info.InstanceClass == ServerlessClass {
+		// already includes the I/O-Optimized rate
+		cost.Compute = info.AvgACU * AuroraACUHourlyRate(info.StorageType, region) * hoursPerMonth
+	} else {
+		rate, found := RDSHourlyRate(info.InstanceClass, info.Engine, info.LicenseModel, region)
+		cost.Compute = rate * hoursPerMonth
+		if info.StorageType == "aurora-iopt1" {
+			cost.Compute *= AuroraIOOptimizedFactor
+		}
+		if !found {
+			sources = append(sources, PricingDefaultRate)
+		}
+	}
+
+	regionFactor, ok := regionPriceFactor(region)
+	if !ok {
+		sources = append(sources, PricingUSEast1Prices)
+	}
+
+	if !IsAurora(info.Engine) {
+		cost.Storage, cost.IOPS, cost.Throughput = RDSStorageCost(info.Engine, info.StorageType, float64(info.AllocatedStorageGB), float64(info.ProvisionedIOPS), float64(info.StorageThroughput), info.AvgReadIOPS+info.AvgWriteIOPS)
+		cost.Storage *= regionFactor
+		cost.IOPS *= regionFactor
+		cost.Throughput *= regionFactor
+	}
+
+	if RDSDeployment(info) == DeploymentMultiAZ {
+		cost.Compute *= 2
+		cost.Storage *= 2
+		cost.IOPS *= 2
+		cost.Throughput *= 2
+	}
+
+	cost.Backup = RDSBackupStorageCost(info.BackupStorageBilledGB, region)
+
+	cost.Compute = roundCents(cost.Compute)
+	cost.Storage = roundCents(cost.Storage)
+	cost.IOPS = roundCents(cost.IOPS)
+	cost.Throughput = roundCents(cost.Throughput)
+	cost.Backup = roundCents(cost.Backup)
+	cost.Total = roundCents(cost.Compute + cost.Storage + cost.IOPS + cost.Throughput + cost.Backup)
+	cost.PricingSource = PricingCatalog
+	if len(sources) > 0 {
+		cost.PricingSource = strings.Join(sources, "; ")
+	}
+	return cost
+}
+
+// RDSStorageCost prices a Single-AZ volume in us-east-1 by line item: storage,
+// provisioned IOPS and provisioned throughput. avgIOPS is only used for
+// magnetic storage, which bills per I/O request.
+func RDSStorageCost(engine, storageType string, allocatedGB, iops, throughput, avgIOPS float64) (storage, iopsCost, throughputCost float64) {
+	price, ok := rdsStoragePrice[storageType]
+	if !ok {
+		price = rdsStoragePrice["gp2"]
+	}
+	storage = allocatedGB * price
+
+	switch storageType {
+	case "io1", "io2":
+		iopsCost = iops * rdsProvisionedIOPSPrice
+	case "gp3":
+		baseIOPS, baseThroughput := GP3Baseline(engine, allocatedGB)
+		iopsCost = math.Max(0, iops-baseIOPS) * rdsGP3IOPSPrice
+		throughputCost = math.Max(0, throughput-baseThroughput) * rdsGP3ThroughputPrice
+	case "standard":
+		iopsCost = avgIOPS * 3600 * hoursPerMonth / 1e6 * rdsMagneticIOPrice
+	}
+	return storage, iopsCost, throughputCost
+}
+
+// AuroraStorageCost prices the storage and I/O of an Aurora cluster volume for
+// a month. I/O-Optimized (aurora-iopt1) storage has no I/O charges.
+func AuroraStorageCost(storageType string, storageGB, iosPerMonth float64, region string) (storage, io float64) {
+	regionFactor, _ := regionPriceFactor(region)
+	price, ok := rdsStoragePrice[storageType]
+	if !ok {
+		price = rdsStoragePrice["aurora"]
+	}
+	storage = storageGB * price * regionFactor
+	if storageType != "aurora-iopt1" {
+		io = iosPerMonth / 1e6 * auroraIOPrice * regionFactor
+	}
+	return roundCents(storage), roundCents(io)
+}
+
+// AuroraACUHourlyRate is the $/ACU-hour of Aurora Serverless v2 for a
+// cluster storage type and region.
+func AuroraACUHourlyRate(storageType, region string) float64 {
+	rate := auroraACUPrice
+	if storageType == "aurora-iopt1" {
+		rate *= AuroraIOOptimizedFactor
+	}
+	factor, _ := regionPriceFactor(region)
+	return rate * factor
+}
+
+// RDSBackupStorageCost is the monthly cost of backup or snapshot storage
+// beyond the free allowance.
+func RDSBackupStorageCost(storageGB float64, region string) float64 {
+	factor, _ := regionPriceFactor(region)
+	return storageGB * rdsBackupPrice * factor
+}
+
+// GP3Limits are the gp3 volume limits of an engine.
+type GP3Limits struct {
+	ProvisionedMinGB int64 // smaller volumes get the baseline only
+	Striped          bool  // volumes of ProvisionedMinGB or more get the higher baseline
+	MaxIOPS          int64
+	MaxThroughput    int64 // MiB/s
+}
+
+// GetGP3Limits returns the gp3 limits of an engine. Oracle volumes are
+// striped from 200 GB and SQL Server volumes never are, but can be provisioned
+// above the baseline at any size up to lower maximums.
+func GetGP3Limits(engine string) GP3Limits {
+	switch {
+	case strings.HasPrefix(engine, "oracle"):
+		return GP3Limits{ProvisionedMinGB: 200, Striped: true, MaxIOPS: 64000, MaxThroughput: 4000}
+	case strings.HasPrefix(engine, "sqlserver"):
+		return GP3Limits{ProvisionedMinGB: 20, Striped: false, MaxIOPS: 16000, MaxThroughput: 1000}
+	default:
+		return GP3Limits{ProvisionedMinGB: 400, Striped: true, MaxIOPS: 64000, MaxThroughput: 4000}
+	}
+}
+
+// GP3Baseline is the IOPS and MiB/s included with an RDS gp3 volume of an
+// engine.
+func GP3Baseline(engine string, allocatedGB float64) (iops, throughput float64) {
+	limits := GetGP3Limits(engine)
+	if limits.Striped && allocatedGB >= float64(limits.ProvisionedMinGB) {
+		return 12000, 500
+	}
+	return 3000, 125
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
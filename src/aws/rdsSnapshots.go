@@ -0,0 +1,70 @@
+package awsclient
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// RDSSnapshotInfo is a manual or automated snapshot of an RDS instance or
+// Aurora cluster with its estimated storage cost.
+type RDSSnapshotInfo struct {
+	SnapshotID         string     `json:"snapshotId"`
+	SnapshotArn        *string    `json:"snapshotArn"`
+	SnapshotType       string     `json:"snapshotType"`
+	SourceID           string     `json:"sourceId"`
+	SourceRegion       string     `json:"sourceRegion"`
+	IsCluster          bool       `json:"isCluster"`
+	Engine             string     `json:"engine"`
+	EngineVersion      string     `json:"engineVersion"`
+	CreateTime         *time.Time `json:"createTime"`
+	AgeDays            int        `json:"ageDays"`
+	AllocatedStorageGB int64      `json:"allocatedStorageGB"`
+	EstimatedBilledGB  float64    `json:"estimatedBilledGB"`
+	MonthlyCost        float64    `json:"monthlyCost"`
+	IsOrphaned         bool       `json:"isOrphaned"`
+	IsOld              bool       `json:"isOld"`
+	Recommendation     string     `json:"recommendation"`
+}
+
+// NewRDSSnapshotInfo maps an instance snapshot onto RDSSnapshotInfo.
+func NewRDSSnapshotInfo(s rdstypes.DBSnapshot) RDSSnapshotInfo {
+	return RDSSnapshotInfo{
+		SnapshotID:         aws.ToString(s.DBSnapshotIdentifier),
+		SnapshotArn:        s.DBSnapshotArn,
+		SnapshotType:       aws.ToString(s.SnapshotType),
+		SourceID:           aws.ToString(s.DBInstanceIdentifier),
+		SourceRegion:       aws.ToString(s.SourceRegion),
+		Engine:             aws.ToString(s.Engine),
+		EngineVersion:      aws.ToString(s.EngineVersion),
+		CreateTime:         s.SnapshotCreateTime,
+		AllocatedStorageGB: int64(aws.ToInt32(s.AllocatedStorage)),
+	}
+}
+
+// NewRDSClusterSnapshotInfo maps a cluster snapshot onto RDSSnapshotInfo.
+func NewRDSClusterSnapshotInfo(s rdstypes.DBClusterSnapshot) RDSSnapshotInfo {
+	return RDSSnapshotInfo{
+		SnapshotID:         aws.ToString(s.DBClusterSnapshotIdentifier),
+		SnapshotArn:        s.DBClusterSnapshotArn,
+		SnapshotType:       aws.ToString(s.SnapshotType),
+		SourceID:           aws.ToString(s.DBClusterIdentifier),
+		SourceRegion:       arnRegion(aws.ToString(s.SourceDBClusterSnapshotArn)),
+		IsCluster:          true,
+		Engine:             aws.ToString(s.Engine),
+		EngineVersion:      aws.ToString(s.EngineVersion),
+		CreateTime:         s.SnapshotCreateTime,
+		AllocatedStorageGB: int64(aws.ToInt32(s.AllocatedStorage)),
+	}
+}
+
+// arnRegion returns the region of an ARN, or "" if it does not parse.
+func arnRegion(s string) string {
+	a, err := arn.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return a.Region
+}
@@ -0,0 +1,152 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+const (
+	// target utilization we recommend for autoscaled capacity
+	recommendedTargetUtilization float64 = 70
+	// headroom kept above the observed peak when recommending max capacity
+	autoScalingMaxHeadroom float64 = 1.2
+)
+
+// analyzeAutoScaledCapacity replays the provisioned capacity time series of
+// an autoscaled table or GSI and recommends min, max and target settings.
+func analyzeAutoScaledCapacity(in capacityInput) capacityAnalysis {
+	var (
+		currentCost   float64
+		projectedCost float64
+		advice        []awsclient.AutoScalingAdvice
+		changes       []string
+	)
+
+	sides := []struct {
+		name        string
+		scaling     *awsclient.AutoScalingSettings
+		capacity    int64
+		consumed    []float64
+		provisioned []float64
+		price       float64
+	}{
+		{"read", in.readScaling, in.readCap, in.consumedRead, in.provisionedRead, rcuPrice},
+		{"write", in.writeScaling, in.writeCap, in.consumedWrite, in.provisionedWrite, wcuPrice},
+	}
+
+	for _, side := range sides {
+		if side.scaling == nil {
+			fixed := float64(side.capacity) * side.price * in.hours
+			currentCost += fixed
+			projectedCost += fixed
+			continue
+		}
+
+		a := adviseAutoScaling(side.scaling, side.capacity, side.consumed, side.provisioned, side.price)
+		currentCost += a.CurrentCost
+		projectedCost += a.ProjectedCost
+		advice = append(advice, a)
+
+		if a.RecommendedMin != a.CurrentMin || a.RecommendedMax != a.CurrentMax || a.RecommendedTarget != a.CurrentTarget {
+			changes = append(changes, fmt.Sprintf("%s min %d→%d, max %d→%d, target %.0f%%→%.0f%%",
+				side.name, a.CurrentMin, a.RecommendedMin, a.CurrentMax, a.RecommendedMax, a.CurrentTarget, a.RecommendedTarget))
+		}
+	}
+
+	avgProvisionedRead := float64(in.readCap)
+	if len(in.provisionedRead) > 0 {
+		avgProvisionedRead = mean(in.provisionedRead)
+	}
+	avgProvisionedWrite := float64(in.writeCap)
+	if len(in.provisionedWrite) > 0 {
+		avgProvisionedWrite = mean(in.provisionedWrite)
+	}
+	utilization := utilizationPct(avgProvisionedRead, avgProvisionedWrite, in.avgRead, in.avgWrite)
+
+	potentialSavings := max(0, currentCost-projectedCost)
+	percent := 0.0
+	if currentCost > 0 {
+		percent = 100 * potentialSavings / currentCost
+	}
+
+	rec := "✅ Autoscaling settings OK"
+	needOptimisation := false
+	if len(changes) > 0 && percent >= AUTOSCALING_MIN_SAVINGS_PCT {
+		rec = "🔧 Tune autoscaling: " + strings.Join(changes, "; ")
+		needOptimisation = true
+	}
+
+	return capacityAnalysis{
+		utilization:       utilization,
+		currentCost:       currentCost,
+		actualCost:        projectedCost,
+		potentialSavings:  potentialSavings,
+		percent:           percent,
+		recommendation:    rec,
+		needOptimisation:  needOptimisation,
+		autoScalingAdvice: advice,
+		rightSizedCost:    projectedCost,
+		onDemandCost:      rightSize(in).onDemandCost,
+	}
+}
+
+// adviseAutoScaling sizes min capacity to the quiet hours and max capacity to
+// the peak, both at the recommended target utilization, and prices the
+// resulting provisioned series against the one observed.
+func adviseAutoScaling(s *awsclient.AutoScalingSettings, capacity int64, consumed, provisioned []float64, price float64) awsclient.AutoScalingAdvice {
+	a := awsclient.AutoScalingAdvice{
+		ScalableDimension: s.ScalableDimension,
+		CurrentMin:        s.MinCapacity,
+		CurrentMax:        s.MaxCapacity,
+		CurrentTarget:     s.TargetUtilization,
+		RecommendedMin:    s.MinCapacity,
+		RecommendedMax:    s.MaxCapacity,
+		RecommendedTarget: s.TargetUtilization,
+	}
+
+	if len(provisioned) > 0 {
+		a.CurrentCost = sum(provisioned) * price
+	} else {
+		a.CurrentCost = float64(capacity) * price * float64(len(consumed))
+	}
+	a.ProjectedCost = a.CurrentCost
+
+	if len(consumed) == 0 {
+		return a
+	}
+
+	a.RecommendedTarget = max(s.TargetUtilization, recommendedTargetUtilization)
+	target := a.RecommendedTarget / 100
+
+	a.RecommendedMin = int32(max(1, math.Ceil(stats.Percentile(consumed, 10)/target)))
+	a.RecommendedMax = int32(max(float64(a.RecommendedMin), math.Ceil(slices.Max(consumed)/target*autoScalingMaxHeadroom)))
+
+	var projected float64
+	for _, c := range consumed {
+		projected += min(max(c/target, float64(a.RecommendedMin)), float64(a.RecommendedMax))
+	}
+	a.ProjectedCost = projected * price
+	a.PotentialSavings = stats.Round(max(0, a.CurrentCost-a.ProjectedCost), 2)
+	a.CurrentCost = stats.Round(a.CurrentCost, 2)
+	a.ProjectedCost = stats.Round(a.ProjectedCost, 2)
+	return a
+}
+
+func sum(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return sum(values) / float64(len(values))
+}
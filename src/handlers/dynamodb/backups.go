@@ -0,0 +1,75 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	pitrPrice          float64 = 0.20 // $ per GB-month of table data
+	backupStoragePrice float64 = 0.10 // $ per GB-month, on-demand backup
+)
+
+// analyzeBackups prices point-in-time recovery and on-demand backups of a
+// table and flags user backups that are stale or redundant.
+func analyzeBackups(t *awsclient.TableInfo) {
+	if t.PITREnabled {
+		t.PITRMonthlyCost = stats.Round(float64(t.TableSizeMB)/1024*pitrPrice, 2)
+	}
+
+	// newest first, so the first BACKUP_KEEP_LATEST user backups are kept;
+	// backups without a creation time go last
+	sort.SliceStable(t.Backups, func(i, j int) bool {
+		a, b := t.Backups[i].CreatedAt, t.Backups[j].CreatedAt
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.After(*b)
+	})
+
+	var (
+		totalCost  float64
+		savings    float64
+		userKept   int
+		staleCount int64
+		flagged    int
+	)
+	for i := range t.Backups {
+		b := &t.Backups[i]
+		b.MonthlyCost = stats.Round(b.SizeMB/1024*backupStoragePrice, 2)
+		totalCost += b.SizeMB / 1024 * backupStoragePrice
+		t.BackupSizeMB += b.SizeMB
+
+		// AWS Backup and system backups follow their own retention rules
+		if b.BackupType != "USER" {
+			continue
+		}
+		b.Stale = b.CreatedAt != nil && time.Since(*b.CreatedAt) > BACKUP_MAX_AGE_DAYS*24*time.Hour
+		b.Redundant = userKept >= BACKUP_KEEP_LATEST
+		userKept++
+
+		if b.Stale {
+			staleCount++
+		}
+		if b.Stale || b.Redundant {
+			flagged++
+			savings += b.SizeMB / 1024 * backupStoragePrice
+		}
+	}
+
+	t.BackupCount = int64(len(t.Backups))
+	t.BackupSizeMB = stats.Round(t.BackupSizeMB, 2)
+	t.BackupMonthlyCost = stats.Round(totalCost, 2)
+	t.StaleBackupCount = staleCount
+	t.BackupSavings = stats.Round(savings, 2)
+
+	switch {
+	case flagged > 0:
+		t.BackupRecommendation = fmt.Sprintf("⚠️ Delete %d stale or redundant backups (saves $%.2f/month)", flagged, savings)
+	case t.BackupCount > 0 || t.PITREnabled:
+		t.BackupRecommendation = "✅ Backups OK"
+	}
+}
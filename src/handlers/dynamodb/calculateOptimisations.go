@@ -2,6 +2,7 @@ package dynamodb
 
 import (
 	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
 	"cost-optimisation/src/storage"
 	"encoding/json"
 	"fmt"
@@ -9,6 +10,14 @@ import (
 	"sort"
 )
 
+const (
+	rcuPrice      float64 = 0.00013     // $ per RCU-hour
+	wcuPrice      float64 = 0.00065     // $ per WCU-hour
+	rruPrice      float64 = 0.125 / 1e6 // $ per on-demand read request unit
+	wruPrice      float64 = 0.625 / 1e6 // $ per on-demand write request unit
+	hoursPerMonth float64 = 720
+)
+
 func OptimiseAnalyse(dataPath string, outputPath string) {
 	fmt.Println("Start optimisation analysis")
 	data, err := os.ReadFile(dataPath)
@@ -24,14 +33,24 @@ func OptimiseAnalyse(dataPath string, outputPath string) {
 	savingsSumm := 0.0
 
 	for i, t := range tables {
+		analyzeTableClass(&tables[i])
+		analyzeIdleTable(&tables[i])
+		analyzeBackups(&tables[i])
+		analyzeReplicas(&tables[i])
+		analyzeStreams(&tables[i])
+		tables[i].Simulation = simulateTable(&tables[i])
 		if t.BillingMode == "PROVISIONED" {
 			analyzeProvisionedTable(&tables[i])
-			savingsSumm += tables[i].PotentialSavings
-		} else {
-			continue
 		}
+		// runs last so a throttled table drops any cost-cutting advice
+		analyzeThrottling(&tables[i])
+		savingsSumm += tables[i].PotentialSavings
 	}
 
+	reserved := recommendReservedCapacity(tables)
+	storage.WriteToJSON(RESERVED_PATH, reserved)
+	storage.WriteToCSV(RESERVED_PATH, reserved)
+
 	sort.Slice(tables, func(i, j int) bool {
 		return tables[i].PotentialSavings > tables[j].PotentialSavings
 	})
@@ -47,15 +66,169 @@ func OptimiseAnalyse(dataPath string, outputPath string) {
 }
 
 func analyzeProvisionedTable(t *awsclient.TableInfo) {
-	const (
-		rcuPrice float64 = 0.00013
-		wcuPrice float64 = 0.00065
-		hours14d float64 = 24 * 14
-	)
+	base := analyzeCapacity(tableCapacity(t))
+	hours := lookbackHours(t)
+
+	currentCost := base.currentCost
+	actualCost := base.actualCost
+	potentialSavings := base.potentialSavings
+	needOptimisation := base.needOptimisation
+	rightSizedCost := base.rightSizedCost
+	onDemandCost := base.onDemandCost
+
+	// GSIs are billed on their own capacity, so roll them up into the table totals
+	for i := range t.GSIs {
+		gsi := &t.GSIs[i]
+		a := analyzeCapacity(indexCapacity(gsi, hours))
+
+		gsi.UtilizationPct = a.utilization
+		gsi.CurrentCost = stats.Round(a.currentCost, 2)
+		gsi.ActualCost = stats.Round(a.actualCost, 2)
+		gsi.PotentialSavings = stats.Round(a.potentialSavings, 2)
+		gsi.PotentialSavingsP = stats.Round(a.percent, 1)
+		gsi.Recommendation = a.recommendation
+		gsi.NeedOptimisation = a.needOptimisation
+		gsi.AutoScalingAdvice = a.autoScalingAdvice
+		gsi.RecommendedRCU = a.recommendedRCU
+		gsi.RecommendedWCU = a.recommendedWCU
+		gsi.RightSizedCost = stats.Round(a.rightSizedCost, 2)
+		gsi.OnDemandCost = stats.Round(a.onDemandCost, 2)
+		gsi.SavingsVsCurrent = stats.Round(a.currentCost-a.rightSizedCost, 2)
+		gsi.SavingsVsOnDemand = stats.Round(a.onDemandCost-a.rightSizedCost, 2)
+		rightSizedCost += a.rightSizedCost
+		onDemandCost += a.onDemandCost
+
+		currentCost += a.currentCost
+		actualCost += a.actualCost
+		potentialSavings += a.potentialSavings
+		needOptimisation = needOptimisation || a.needOptimisation
+	}
+
+	percent := 0.0
+	if currentCost > 0 {
+		percent = 100 * potentialSavings / currentCost
+	}
+
+	rec := base.recommendation
+	if !base.needOptimisation && needOptimisation {
+		rec = "⚠️ Table OK, but some GSIs are over-provisioned (see gsis)"
+	}
+
+	t.UtilizationPct = base.utilization
+	t.CurrentCost = stats.Round(currentCost, 2)
+	t.ActualCost = stats.Round(actualCost, 2)
+	t.PotentialSavings = stats.Round(potentialSavings, 2)
+	t.PotentialSavingsP = stats.Round(percent, 1)
+	t.Recommendation = rec
+	t.NeedOptimisation = needOptimisation
+	t.AutoScalingAdvice = base.autoScalingAdvice
+
+	// recommended units are the base table's; costs include the GSIs
+	t.RecommendedRCU = base.recommendedRCU
+	t.RecommendedWCU = base.recommendedWCU
+	t.RightSizedCost = stats.Round(rightSizedCost, 2)
+	t.OnDemandCost = stats.Round(onDemandCost, 2)
+	t.SavingsVsCurrent = stats.Round(currentCost-rightSizedCost, 2)
+	t.SavingsVsOnDemand = stats.Round(onDemandCost-rightSizedCost, 2)
 
-	currentCost := (float64(t.ReadCapacityUnits)*rcuPrice + float64(t.WriteCapacityUnits)*wcuPrice) * hours14d
-	actualCost := (t.AvgConsumedRead*rcuPrice + t.AvgConsumedWrite*wcuPrice) * hours14d
-	utilization := ((t.AvgConsumedRead/float64(t.ReadCapacityUnits) + t.AvgConsumedWrite/float64(t.WriteCapacityUnits)) / 2) * 100
+}
+
+// capacityInput is the provisioned capacity and usage of a table or a GSI.
+type capacityInput struct {
+	readCap          int64
+	writeCap         int64
+	avgRead          float64
+	avgWrite         float64
+	readScaling      *awsclient.AutoScalingSettings
+	writeScaling     *awsclient.AutoScalingSettings
+	consumedRead     []float64
+	consumedWrite    []float64
+	provisionedRead  []float64
+	provisionedWrite []float64
+	hours            float64 // length of the lookback window
+	index            bool    // a GSI, whose billing mode follows the table
+}
+
+func tableCapacity(t *awsclient.TableInfo) capacityInput {
+	return capacityInput{
+		hours:            lookbackHours(t),
+		readCap:          t.ReadCapacityUnits,
+		writeCap:         t.WriteCapacityUnits,
+		avgRead:          t.AvgConsumedRead,
+		avgWrite:         t.AvgConsumedWrite,
+		readScaling:      t.ReadAutoScaling,
+		writeScaling:     t.WriteAutoScaling,
+		consumedRead:     t.ConsumedReadSeries,
+		consumedWrite:    t.ConsumedWriteSeries,
+		provisionedRead:  t.ProvisionedReadSeries,
+		provisionedWrite: t.ProvisionedWriteSeries,
+	}
+}
+
+func indexCapacity(g *awsclient.GSIInfo, hours float64) capacityInput {
+	return capacityInput{
+		hours:            hours,
+		index:            true,
+		readCap:          g.ReadCapacityUnits,
+		writeCap:         g.WriteCapacityUnits,
+		avgRead:          g.AvgConsumedRead,
+		avgWrite:         g.AvgConsumedWrite,
+		readScaling:      g.ReadAutoScaling,
+		writeScaling:     g.WriteAutoScaling,
+		consumedRead:     g.ConsumedReadSeries,
+		consumedWrite:    g.ConsumedWriteSeries,
+		provisionedRead:  g.ProvisionedReadSeries,
+		provisionedWrite: g.ProvisionedWriteSeries,
+	}
+}
+
+// capacityInputs returns the table followed by each of its GSIs.
+func capacityInputs(t *awsclient.TableInfo) []capacityInput {
+	inputs := []capacityInput{tableCapacity(t)}
+	for i := range t.GSIs {
+		inputs = append(inputs, indexCapacity(&t.GSIs[i], inputs[0].hours))
+	}
+	return inputs
+}
+
+// lookbackHours is the window the table's metrics cover. Older scans did not
+// record it and always used TIME_FRAME_DAYS.
+func lookbackHours(t *awsclient.TableInfo) float64 {
+	if t.LookbackHours > 0 {
+		return float64(t.LookbackHours)
+	}
+	return 24 * TIME_FRAME_DAYS
+}
+
+type capacityAnalysis struct {
+	utilization       float64
+	currentCost       float64
+	actualCost        float64
+	potentialSavings  float64
+	percent           float64
+	recommendation    string
+	needOptimisation  bool
+	autoScalingAdvice []awsclient.AutoScalingAdvice
+
+	// right-sizing targets; autoscaled capacity has no fixed target and
+	// reports its tuned autoscaling cost as rightSizedCost instead
+	recommendedRCU int64
+	recommendedWCU int64
+	rightSizedCost float64
+	onDemandCost   float64
+}
+
+// analyzeCapacity compares provisioned and average consumed capacity of a
+// table or a GSI. Autoscaled capacity is tuned rather than switched to
+// on-demand, since the provisioned value already follows the traffic.
+func analyzeCapacity(in capacityInput) capacityAnalysis {
+	if in.readScaling != nil || in.writeScaling != nil {
+		return analyzeAutoScaledCapacity(in)
+	}
+
+	currentCost := (float64(in.readCap)*rcuPrice + float64(in.writeCap)*wcuPrice) * in.hours
+	actualCost := (in.avgRead*rcuPrice + in.avgWrite*wcuPrice) * in.hours
+	utilization := utilizationPct(float64(in.readCap), float64(in.writeCap), in.avgRead, in.avgWrite)
 
 	if currentCost < 0.0001 {
 		currentCost = 0.0
@@ -74,29 +247,54 @@ func analyzeProvisionedTable(t *awsclient.TableInfo) {
 		percent = 100 * potentialSavings / currentCost
 	}
 
+	rs := rightSize(in)
+
 	rec := ""
 	needOptimisation := false
 	if utilization < 50 {
-		rec = "⚠️ Consider switching to PAY_PER_REQUEST (utilization too low)"
+		if in.index {
+			// billing mode is set per table, so a GSI can only be right-sized
+			rec = fmt.Sprintf("⚠️ Right-size GSI to %d RCU / %d WCU (utilization too low, saves $%.2f)", rs.rcu, rs.wcu, currentCost-rs.cost)
+		} else if rs.onDemandCost < rs.cost {
+			rec = fmt.Sprintf("⚠️ Consider switching to PAY_PER_REQUEST (utilization too low, on-demand $%.2f vs right-sized $%.2f)", rs.onDemandCost, rs.cost)
+		} else {
+			rec = fmt.Sprintf("⚠️ Right-size to %d RCU / %d WCU (utilization too low, saves $%.2f)", rs.rcu, rs.wcu, currentCost-rs.cost)
+		}
 		needOptimisation = true
 	} else {
 		rec = "✅ OK to stay PROVISIONED"
 	}
 
-	t.UtilizationPct = utilization
-	t.CurrentCost = round(currentCost, 2)
-	t.ActualCost = round(actualCost, 2)
-	t.PotentialSavings = round(potentialSavings, 2)
-	t.PotentialSavingsP = round(percent, 1)
-	t.Recommendation = rec
-	t.NeedOptimisation = needOptimisation
-
+	return capacityAnalysis{
+		utilization:      utilization,
+		currentCost:      currentCost,
+		actualCost:       actualCost,
+		potentialSavings: potentialSavings,
+		percent:          percent,
+		recommendation:   rec,
+		needOptimisation: needOptimisation,
+		recommendedRCU:   rs.rcu,
+		recommendedWCU:   rs.wcu,
+		rightSizedCost:   rs.cost,
+		onDemandCost:     rs.onDemandCost,
+	}
 }
 
-func round(val float64, precision int) float64 {
-	format := fmt.Sprintf("%%.%df", precision)
-	str := fmt.Sprintf(format, val)
-	var out float64
-	fmt.Sscanf(str, "%f", &out)
-	return out
+// utilizationPct averages read and write utilization, skipping a side
+// with no provisioned capacity instead of dividing by zero.
+func utilizationPct(readCap, writeCap, avgRead, avgWrite float64) float64 {
+	var total float64
+	var sides int
+	if readCap > 0 {
+		total += avgRead / readCap
+		sides++
+	}
+	if writeCap > 0 {
+		total += avgWrite / writeCap
+		sides++
+	}
+	if sides == 0 {
+		return 0
+	}
+	return total / float64(sides) * 100
 }
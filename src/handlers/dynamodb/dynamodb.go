@@ -17,7 +17,40 @@ const (
 	ROOT               = "/Users/c-andrew.mironov/Work/cost-optimisation/"
 	TABLES_PATH        = ROOT + "data/tables.json"
 	COST_ANALYSIS_PATH = ROOT + "data/cost_analysis.json"
+	RESERVED_PATH      = ROOT + "data/reserved_capacity"
 	TIME_FRAME_DAYS    = 14
+
+	// Right-sizing: recommended capacity is this percentile of hourly consumed
+	// capacity plus the headroom percentage on top.
+	RIGHT_SIZING_PERCENTILE   = 99
+	RIGHT_SIZING_HEADROOM_PCT = 20
+
+	// Autoscaling settings are only worth tuning when the new settings save
+	// at least this share of the current cost.
+	AUTOSCALING_MIN_SAVINGS_PCT = 5
+
+	// Tables that consumed fewer request units than this over the whole
+	// lookback window are considered idle.
+	IDLE_MAX_REQUEST_UNITS = 1000
+
+	// A table runs hot in an hour when consumption exceeds this share of
+	// provisioned capacity; it is under-provisioned when that happens in more
+	// than HOT_HOURS_PCT of the hours.
+	HOT_UTILIZATION_PCT = 80
+	HOT_HOURS_PCT       = 5
+
+	// On-demand backups older than this are stale; user backups beyond the
+	// newest BACKUP_KEEP_LATEST are redundant.
+	BACKUP_MAX_AGE_DAYS = 90
+	BACKUP_KEEP_LATEST  = 3
+
+	// Global table replicas serving less than this share of the table's reads
+	// across all regions are removal candidates.
+	REPLICA_MIN_READ_SHARE_PCT = 1
+
+	// Autoscaling scenario of the billing-mode simulation
+	SIMULATION_TARGET_UTILIZATION_PCT = 70
+	SIMULATION_MAX_DECREASES_PER_DAY  = 4
 )
 
 func AnalyzeDynamdoDB() {
@@ -25,7 +58,7 @@ func AnalyzeDynamdoDB() {
 	ctx := context.Background()
 	client := awsclient.NewAWSClient(awsclient.AWSClientOpts{
 		Region:        constants.US_WEST_2,
-		TimeFrameDays: 14,
+		TimeFrameDays: TIME_FRAME_DAYS,
 	})
 
 	fileWriter := storage.NewFileWriter(TABLES_PATH)
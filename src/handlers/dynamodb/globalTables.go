@@ -0,0 +1,79 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"strings"
+)
+
+const (
+	rwcuPrice float64 = 0.00065     // $ per replicated WCU-hour
+	rwruPrice float64 = 0.625 / 1e6 // $ per replicated on-demand write request unit
+)
+
+// analyzeReplicas prices each global table replica: the writes replicated
+// into its region, its storage and its own read capacity. Every write made in
+// the scanned region is assumed to be replicated to each replica. Replicas
+// that serve almost none of the table's reads are removal candidates, judged
+// only when the reads of every region were read and add up to something.
+func analyzeReplicas(t *awsclient.TableInfo) {
+	if len(t.Replicas) == 0 {
+		return
+	}
+
+	hours := lookbackHours(t)
+	scale := hoursPerMonth / hours
+	storageCost := tableStorageGB(t) * storagePrice
+
+	writeCost := requestUnits(t.ConsumedWriteSeries, t.AvgConsumedWrite, hours) * scale * rwruPrice
+	if t.BillingMode == "PROVISIONED" {
+		writeCost = provisionedAverage(t.ProvisionedWriteSeries, t.WriteCapacityUnits) * rwcuPrice * hoursPerMonth
+	}
+
+	totalReads := requestUnits(t.ConsumedReadSeries, t.AvgConsumedRead, hours)
+	readsKnown := t.UsageSeriesAvailable
+	for _, r := range t.Replicas {
+		totalReads += r.ReadRequestUnits
+		readsKnown = readsKnown && r.MetricsAvailable
+	}
+	judgeShares := readsKnown && totalReads > 0
+
+	var (
+		replicatedWrites float64
+		savings          float64
+		candidates       []string
+	)
+	for i := range t.Replicas {
+		r := &t.Replicas[i]
+
+		readCost := r.ReadRequestUnits * scale * rruPrice
+		if t.BillingMode == "PROVISIONED" {
+			readCost = float64(r.ReadCapacityUnits) * rcuPrice * hoursPerMonth
+		}
+
+		r.ReplicatedWriteMonthlyCost = stats.Round(writeCost, 2)
+		r.MonthlyCost = stats.Round(writeCost+storageCost+readCost, 2)
+		replicatedWrites += writeCost
+
+		if judgeShares && 100*r.ReadRequestUnits/totalReads < REPLICA_MIN_READ_SHARE_PCT {
+			r.RemovalCandidate = true
+			r.PotentialSavings = r.MonthlyCost
+			savings += r.MonthlyCost
+			candidates = append(candidates, r.RegionName)
+		}
+	}
+
+	t.ReplicatedWriteMonthlyCost = stats.Round(replicatedWrites, 2)
+	t.ReplicaSavings = stats.Round(savings, 2)
+	switch {
+	case len(candidates) > 0:
+		t.ReplicaRecommendation = fmt.Sprintf("⚠️ Remove under-used replicas %s (saves $%.2f/month)", strings.Join(candidates, ", "), savings)
+	case !readsKnown:
+		t.ReplicaRecommendation = "❔ Read metrics missing for some regions, replica usage unknown"
+	case totalReads == 0:
+		t.ReplicaRecommendation = "❔ No reads in any region, replica usage unknown"
+	default:
+		t.ReplicaRecommendation = "✅ All replicas serve reads"
+	}
+}
@@ -0,0 +1,130 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+)
+
+// reservedOffer is the price of 100 units of DynamoDB reserved capacity
+// (us-east-1, Standard table class).
+type reservedOffer struct {
+	capacityType string
+	term         string
+	termHours    float64
+	upfront      float64 // $ per 100 units
+	hourly       float64 // $ per 100 units per hour
+	onDemand     float64 // provisioned rate, $ per 100 units per hour
+}
+
+var reservedOffers = []reservedOffer{
+	{"RCU", "1-year", 365 * 24, 30, 0.0025, rcuPrice * 100},
+	{"RCU", "3-year", 3 * 365 * 24, 36, 0.0016, rcuPrice * 100},
+	{"WCU", "1-year", 365 * 24, 150, 0.0128, wcuPrice * 100},
+	{"WCU", "3-year", 3 * 365 * 24, 180, 0.0081, wcuPrice * 100},
+}
+
+// ReservedCapacityRecommendation is how much reserved capacity to buy in a
+// region for one capacity type and term.
+type ReservedCapacityRecommendation struct {
+	Region              string  `json:"region"`
+	CapacityType        string  `json:"capacityType"`
+	Term                string  `json:"term"`
+	MinSustainedUnits   float64 `json:"minSustainedUnits"`
+	RecommendedUnits    int64   `json:"recommendedUnits"`
+	UpfrontCost         float64 `json:"upfrontCost"`
+	EffectiveHourlyRate float64 `json:"effectiveHourlyRate"`
+	OnDemandHourlyRate  float64 `json:"onDemandHourlyRate"`
+	MonthlySavings      float64 `json:"monthlySavings"`
+	BreakEvenMonths     float64 `json:"breakEvenMonths"`
+}
+
+// recommendReservedCapacity sizes reserved capacity per region to the
+// minimum provisioned RCU/WCU sustained across all Standard-class provisioned
+// tables (GSIs included) during every hour of the lookback window, rounded
+// down to 100-unit blocks. Rates are per 100 units; the effective rate
+// amortizes the upfront fee over the term.
+func recommendReservedCapacity(tables []awsclient.TableInfo) []ReservedCapacityRecommendation {
+	reads := map[string][]float64{}
+	writes := map[string][]float64{}
+
+	for i := range tables {
+		t := &tables[i]
+		if t.BillingMode != "PROVISIONED" || t.TableClass == tableClassIA {
+			continue
+		}
+		region := arnRegion(t.TableArn)
+
+		if reads[region] == nil {
+			reads[region] = make([]float64, int(lookbackHours(t)))
+			writes[region] = make([]float64, int(lookbackHours(t)))
+		}
+		for _, in := range capacityInputs(t) {
+			addCapacity(reads[region], in.provisionedRead, in.readCap)
+			addCapacity(writes[region], in.provisionedWrite, in.writeCap)
+		}
+	}
+
+	var recs []ReservedCapacityRecommendation
+	for _, offer := range reservedOffers {
+		byRegion := reads
+		if offer.capacityType == "WCU" {
+			byRegion = writes
+		}
+
+		for _, region := range slices.Sorted(maps.Keys(byRegion)) {
+			sustained := slices.Min(byRegion[region])
+			blocks := math.Floor(sustained / 100)
+			if blocks < 1 {
+				continue
+			}
+
+			effective := offer.upfront/offer.termHours + offer.hourly
+			hourlySavings := (offer.onDemand - offer.hourly) * blocks
+			rec := ReservedCapacityRecommendation{
+				Region:              region,
+				CapacityType:        offer.capacityType,
+				Term:                offer.term,
+				MinSustainedUnits:   stats.Round(sustained, 0),
+				RecommendedUnits:    int64(blocks) * 100,
+				UpfrontCost:         stats.Round(offer.upfront*blocks, 2),
+				EffectiveHourlyRate: stats.Round(effective, 4),
+				OnDemandHourlyRate:  stats.Round(offer.onDemand, 4),
+				MonthlySavings:      stats.Round((offer.onDemand-effective)*blocks*hoursPerMonth, 2),
+			}
+			if hourlySavings > 0 {
+				rec.BreakEvenMonths = stats.Round(offer.upfront*blocks/(hourlySavings*hoursPerMonth), 1)
+			}
+			recs = append(recs, rec)
+		}
+	}
+	return recs
+}
+
+// addCapacity adds one table's hourly provisioned capacity to a region total.
+// Tables without an autoscaling series hold their fixed capacity every hour.
+func addCapacity(total, series []float64, capacity int64) {
+	for h := range total {
+		if h < len(series) {
+			total[h] += series[h]
+		} else {
+			total[h] += float64(capacity)
+		}
+	}
+}
+
+// arnRegion extracts the region from an ARN such as
+// arn:aws:dynamodb:us-west-2:123456789012:table/name.
+func arnRegion(arn *string) string {
+	if arn == nil {
+		return ""
+	}
+	parts := strings.Split(*arn, ":")
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[3]
+}
@@ -0,0 +1,45 @@
+package dynamodb
+
+import (
+	"cost-optimisation/src/shared/stats"
+	"math"
+)
+
+type rightSizing struct {
+	rcu          int64
+	wcu          int64
+	cost         float64
+	onDemandCost float64
+}
+
+// rightSize computes the provisioned capacity that covers the
+// RIGHT_SIZING_PERCENTILE of hourly consumption plus headroom, and prices it
+// against the same traffic billed on-demand.
+func rightSize(in capacityInput) rightSizing {
+	rcu := recommendedUnits(in.consumedRead, in.avgRead)
+	wcu := recommendedUnits(in.consumedWrite, in.avgWrite)
+
+	return rightSizing{
+		rcu:          rcu,
+		wcu:          wcu,
+		cost:         (float64(rcu)*rcuPrice + float64(wcu)*wcuPrice) * in.hours,
+		onDemandCost: requestUnits(in.consumedRead, in.avgRead, in.hours)*rruPrice + requestUnits(in.consumedWrite, in.avgWrite, in.hours)*wruPrice,
+	}
+}
+
+func recommendedUnits(consumed []float64, avg float64) int64 {
+	peak := avg
+	if len(consumed) > 0 {
+		peak = stats.Percentile(consumed, RIGHT_SIZING_PERCENTILE)
+	}
+	return int64(max(1, math.Ceil(peak*(1+RIGHT_SIZING_HEADROOM_PCT/100.0))))
+}
+
+// requestUnits is the number of on-demand request units the consumed
+// capacity (units per second) adds up to over the lookback window.
+func requestUnits(consumed []float64, avg, hours float64) float64 {
+	if len(consumed) == 0 {
+		return avg * 3600 * hours
+	}
+	return sum(consumed) * 3600
+}
@@ -0,0 +1,63 @@
+package dynamodb
+
+import (
+	"math"
+	"testing"
+)
+
+const testHours = 14 * 24
+
+func TestRightSize(t *testing.T) {
+	ramp := make([]float64, 100) // 1..100 units per second
+	for i := range ramp {
+		ramp[i] = float64(i + 1)
+	}
+
+	tests := []struct {
+		name    string
+		in      capacityInput
+		wantRCU int64
+		wantWCU int64
+	}{
+		{
+			name:    "percentile of the series plus headroom",
+			in:      capacityInput{consumedRead: ramp, consumedWrite: []float64{5, 5, 5}},
+			wantRCU: 119, // p99 99 * 1.2
+			wantWCU: 6,
+		},
+		{
+			name:    "average without a series",
+			in:      capacityInput{avgRead: 10, avgWrite: 2.5},
+			wantRCU: 12,
+			wantWCU: 3,
+		},
+		{
+			name:    "at least one unit",
+			in:      capacityInput{consumedRead: []float64{0, 0}, consumedWrite: nil},
+			wantRCU: 1,
+			wantWCU: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.in.hours = testHours
+			got := rightSize(tt.in)
+			if got.rcu != tt.wantRCU || got.wcu != tt.wantWCU {
+				t.Errorf("rightSize() = %d RCU / %d WCU, want %d / %d", got.rcu, got.wcu, tt.wantRCU, tt.wantWCU)
+			}
+			wantCost := (float64(tt.wantRCU)*rcuPrice + float64(tt.wantWCU)*wcuPrice) * testHours
+			if math.Abs(got.cost-wantCost) > 1e-9 {
+				t.Errorf("rightSize() cost = %v, want %v", got.cost, wantCost)
+			}
+		})
+	}
+}
+
+func TestRightSizeOnDemandCost(t *testing.T) {
+	in := capacityInput{consumedRead: []float64{1, 2}, avgWrite: 1, hours: testHours}
+	got := rightSize(in).onDemandCost
+	want := 3*3600*rruPrice + 1*3600*testHours*wruPrice
+	if math.Abs(got-want) > 1e-12 {
+		t.Errorf("onDemandCost = %v, want %v", got, want)
+	}
+}
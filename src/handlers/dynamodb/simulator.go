@@ -0,0 +1,144 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"math"
+)
+
+const (
+	scenarioCurrent     = "current provisioned"
+	scenarioOnDemand    = "on-demand"
+	scenarioFixed       = "fixed provisioned"
+	scenarioAutoScaling = "autoscaling"
+)
+
+// provisionFunc returns the provisioned capacity for each hour of a consumed
+// series.
+type provisionFunc func(side capacitySide) []float64
+
+// simulateTable replays the hourly consumed capacity of a table and its GSIs
+// under each billing scenario over the lookback window:
+//   - current provisioned: the observed provisioned capacity, only for
+//     PROVISIONED tables; on-demand is the baseline of the others
+//   - on-demand: every consumed unit billed per request
+//   - fixed provisioned: capacity held at the right-sizing target
+//   - autoscaling: target tracking at SIMULATION_TARGET_UTILIZATION_PCT with
+//     DynamoDB's scale-down limits
+//
+// An hour is throttled when consumption in any table or GSI exceeds the
+// capacity provisioned for that hour. Tables whose consumption, or that of a
+// GSI, could not be read are not simulated, as a missing series costs nothing.
+func simulateTable(t *awsclient.TableInfo) []awsclient.SimulationResult {
+	inputs := capacityInputs(t)
+	if !usageSeriesAvailable(t) || len(inputs[0].consumedRead) == 0 && len(inputs[0].consumedWrite) == 0 {
+		return nil
+	}
+	for _, in := range inputs[1:] {
+		if len(in.consumedRead) == 0 || len(in.consumedWrite) == 0 {
+			return nil
+		}
+	}
+
+	scenarios := []struct {
+		name      string
+		provision provisionFunc
+	}{
+		{scenarioCurrent, currentProvisioned},
+		{scenarioOnDemand, nil},
+		{scenarioFixed, fixedProvisioned},
+		{scenarioAutoScaling, autoScaledProvisioned},
+	}
+
+	var results []awsclient.SimulationResult
+	for _, sc := range scenarios {
+		if sc.name == scenarioCurrent && t.BillingMode != "PROVISIONED" {
+			continue
+		}
+		var cost float64
+		throttled := map[int]bool{}
+
+		for _, in := range inputs {
+			for _, side := range capacitySides(in, 0, 0) {
+				if sc.provision == nil {
+					requestPrice := rruPrice
+					if side.name == "write" {
+						requestPrice = wruPrice
+					}
+					cost += sum(side.consumed) * 3600 * requestPrice
+					continue
+				}
+
+				provisioned := sc.provision(side)
+				for h, c := range side.consumed {
+					cost += provisioned[h] * side.price
+					if c > provisioned[h] {
+						throttled[h] = true
+					}
+				}
+			}
+		}
+
+		results = append(results, awsclient.SimulationResult{
+			Scenario:       sc.name,
+			Cost:           stats.Round(cost, 2),
+			ThrottledHours: len(throttled),
+		})
+	}
+	return results
+}
+
+func currentProvisioned(side capacitySide) []float64 {
+	provisioned := make([]float64, len(side.consumed))
+	for h := range provisioned {
+		provisioned[h] = float64(side.capacity)
+		if h < len(side.provisioned) {
+			provisioned[h] = side.provisioned[h]
+		}
+	}
+	return provisioned
+}
+
+func fixedProvisioned(side capacitySide) []float64 {
+	units := float64(recommendedUnits(side.consumed, mean(side.consumed)))
+	provisioned := make([]float64, len(side.consumed))
+	for h := range provisioned {
+		provisioned[h] = units
+	}
+	return provisioned
+}
+
+// autoScaledProvisioned follows target tracking one hour behind the traffic.
+// Increases are unlimited; decreases follow the DynamoDB rule of
+// SIMULATION_MAX_DECREASES_PER_DAY per day plus one more in any hour that
+// follows an hour without a decrease. Existing min/max settings are kept.
+func autoScaledProvisioned(side capacitySide) []float64 {
+	target := SIMULATION_TARGET_UTILIZATION_PCT / 100.0
+	minCap, maxCap := 1.0, math.Inf(1)
+	if side.scaling != nil {
+		minCap = float64(side.scaling.MinCapacity)
+		maxCap = float64(side.scaling.MaxCapacity)
+	}
+
+	provisioned := make([]float64, len(side.consumed))
+	capacity := math.Min(math.Max(math.Ceil(stats.Percentile(side.consumed, 50)/target), minCap), maxCap)
+	decreasesToday, lastDecrease := 0, -2
+
+	for h, c := range side.consumed {
+		if h%24 == 0 {
+			decreasesToday = 0
+		}
+		provisioned[h] = capacity
+
+		desired := math.Min(math.Max(math.Ceil(c/target), minCap), maxCap)
+		switch {
+		case desired > capacity:
+			capacity = desired
+		case desired < capacity && (decreasesToday < SIMULATION_MAX_DECREASES_PER_DAY || lastDecrease < h-1):
+			capacity = desired
+			decreasesToday++
+			lastDecrease = h
+		}
+	}
+	return provisioned
+}
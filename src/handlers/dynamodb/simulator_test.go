@@ -0,0 +1,68 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"slices"
+	"testing"
+)
+
+func TestAutoScaledProvisioned(t *testing.T) {
+	tests := []struct {
+		name string
+		side capacitySide
+		want []float64
+	}{
+		{
+			name: "capacity follows traffic one hour behind",
+			side: capacitySide{consumed: []float64{7, 7, 14, 7}},
+			want: []float64{10, 10, 10, 20},
+		},
+		{
+			name: "min and max are kept",
+			side: capacitySide{
+				scaling:  &awsclient.AutoScalingSettings{MinCapacity: 5, MaxCapacity: 15},
+				consumed: []float64{1, 100},
+			},
+			want: []float64{5, 5},
+		},
+		{
+			name: "decreases are rate limited",
+			side: capacitySide{consumed: []float64{69, 62, 55, 48, 41, 34, 27, 20}},
+			want: []float64{59, 99, 89, 79, 69, 59, 59, 39},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := autoScaledProvisioned(tt.side); !slices.Equal(got, tt.want) {
+				t.Errorf("autoScaledProvisioned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulateTableMissingGSISeries(t *testing.T) {
+	series := []float64{1, 2, 3}
+	tests := []struct {
+		name string
+		gsi  awsclient.GSIInfo
+		want bool
+	}{
+		{"series loaded", awsclient.GSIInfo{UsageSeriesAvailable: true, ConsumedReadSeries: series, ConsumedWriteSeries: series}, true},
+		{"series failed", awsclient.GSIInfo{ConsumedReadSeries: series, ConsumedWriteSeries: series}, false},
+		{"series empty", awsclient.GSIInfo{UsageSeriesAvailable: true, ConsumedReadSeries: series}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := awsclient.TableInfo{
+				BillingMode:          "PAY_PER_REQUEST",
+				UsageSeriesAvailable: true,
+				ConsumedReadSeries:   series,
+				ConsumedWriteSeries:  series,
+				GSIs:                 []awsclient.GSIInfo{tt.gsi},
+			}
+			if got := simulateTable(&table) != nil; got != tt.want {
+				t.Errorf("simulated = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"strings"
+)
+
+const (
+	streamReadPrice     float64 = 0.02 / 100_000 // $ per DynamoDB Streams read request unit
+	streamFreeReads     float64 = 2_500_000      // free stream read request units per month
+	kinesisCDCUnitPrice float64 = 0.10 / 1e6     // $ per change data capture unit
+)
+
+// analyzeStreams prices DynamoDB Streams reads and Kinesis Data Streams
+// change data capture for a table, and flags streams nobody reads.
+//
+// Stream reads made by Lambda triggers are free; the metric cannot tell them
+// apart, so the stream cost is an upper bound. The free tier is per account
+// and region, and is applied per table here. Each write of up to 1 KB is one
+// change data capture unit, so CDC units follow consumed write capacity.
+func analyzeStreams(t *awsclient.TableInfo) {
+	if !t.StreamEnabled && len(t.KinesisDestinations) == 0 {
+		return
+	}
+
+	hours := lookbackHours(t)
+	scale := hoursPerMonth / hours
+	var recs []string
+
+	if t.StreamEnabled {
+		reads := t.StreamReadRequests * scale
+		t.StreamMonthlyCost = stats.Round(max(0, reads-streamFreeReads)*streamReadPrice, 2)
+		switch {
+		case !t.StreamMetricsAvailable:
+			recs = append(recs, "❔ Stream read metrics missing, consumers unknown")
+		case t.StreamReadRequests == 0:
+			recs = append(recs, "⚠️ Stream enabled but has no consumers: disable it")
+		}
+	}
+
+	if len(t.KinesisDestinations) > 0 {
+		cdcUnits := requestUnits(t.ConsumedWriteSeries, t.AvgConsumedWrite, hours) * scale
+		t.KinesisMonthlyCost = stats.Round(cdcUnits*kinesisCDCUnitPrice*float64(len(t.KinesisDestinations)), 2)
+		if t.StreamEnabled {
+			recs = append(recs, fmt.Sprintf("Both DynamoDB Streams and %d Kinesis destinations are enabled: check both are needed", len(t.KinesisDestinations)))
+		}
+	}
+
+	if len(recs) == 0 {
+		t.StreamRecommendation = "✅ Streams in use"
+		return
+	}
+	t.StreamRecommendation = strings.Join(recs, "; ")
+}
@@ -0,0 +1,96 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+)
+
+// Standard-Infrequent Access prices (us-east-1). Storage is cheaper and
+// throughput is dearer than the Standard table class.
+const (
+	iaRCUPrice     float64 = 0.00016     // $ per RCU-hour
+	iaWCUPrice     float64 = 0.00081     // $ per WCU-hour
+	iaRRUPrice     float64 = 0.155 / 1e6 // $ per on-demand read request unit
+	iaWRUPrice     float64 = 0.78 / 1e6  // $ per on-demand write request unit
+	storagePrice   float64 = 0.25        // $ per GB-month, Standard
+	iaStoragePrice float64 = 0.10        // $ per GB-month, Standard-IA
+)
+
+const (
+	tableClassStandard = "STANDARD"
+	tableClassIA       = "STANDARD_INFREQUENT_ACCESS"
+)
+
+// analyzeTableClass prices a table (including its GSIs) under the Standard
+// and Standard-IA classes and recommends the cheaper one. IA wins once
+// storage dominates throughput; BreakEvenRatio is the storage-to-throughput
+// cost ratio (at Standard prices) where both classes cost the same.
+func analyzeTableClass(t *awsclient.TableInfo) {
+	storageGB := tableStorageGB(t)
+	stdThroughput, iaThroughput := monthlyThroughputCost(t)
+	stdStorage := storageGB * storagePrice
+	iaStorage := storageGB * iaStoragePrice
+
+	standardCost := stdStorage + stdThroughput
+	iaCost := iaStorage + iaThroughput
+
+	t.StandardClassCost = stats.Round(standardCost, 2)
+	t.IAClassCost = stats.Round(iaCost, 2)
+	if stdThroughput > 0 {
+		t.StorageToThroughput = stats.Round(stdStorage/stdThroughput, 2)
+		// stdStorage + stdThroughput == iaStorage + iaThroughput, solved for the ratio
+		t.BreakEvenRatio = stats.Round((iaThroughput/stdThroughput-1)/(1-iaStoragePrice/storagePrice), 2)
+	}
+
+	current, alternative, target := standardCost, iaCost, tableClassIA
+	if t.TableClass == tableClassIA {
+		current, alternative, target = iaCost, standardCost, tableClassStandard
+	}
+
+	if alternative < current {
+		t.TableClassRecommendation = fmt.Sprintf("⚠️ Switch table class to %s (saves $%.2f/month)", target, current-alternative)
+	} else {
+		t.TableClassRecommendation = fmt.Sprintf("✅ OK to stay %s", t.TableClass)
+	}
+}
+
+// monthlyThroughputCost returns the monthly read/write cost of a table and its
+// GSIs at Standard and at Standard-IA prices.
+func monthlyThroughputCost(t *awsclient.TableInfo) (standard, ia float64) {
+	for _, in := range capacityInputs(t) {
+		if t.BillingMode == "PROVISIONED" {
+			read := provisionedAverage(in.provisionedRead, in.readCap)
+			write := provisionedAverage(in.provisionedWrite, in.writeCap)
+			standard += (read*rcuPrice + write*wcuPrice) * hoursPerMonth
+			ia += (read*iaRCUPrice + write*iaWCUPrice) * hoursPerMonth
+			continue
+		}
+
+		// request units over the lookback window, scaled to a month
+		scale := hoursPerMonth / in.hours
+		reads := requestUnits(in.consumedRead, in.avgRead, in.hours) * scale
+		writes := requestUnits(in.consumedWrite, in.avgWrite, in.hours) * scale
+		standard += reads*rruPrice + writes*wruPrice
+		ia += reads*iaRRUPrice + writes*iaWRUPrice
+	}
+	return standard, ia
+}
+
+// provisionedAverage is the mean of the provisioned capacity series when
+// autoscaling moves it, or the fixed capacity otherwise.
+func provisionedAverage(series []float64, capacity int64) float64 {
+	if len(series) > 0 {
+		return mean(series)
+	}
+	return float64(capacity)
+}
+
+// tableStorageGB is the billed storage of a table including its GSIs.
+func tableStorageGB(t *awsclient.TableInfo) float64 {
+	storageGB := float64(t.TableSizeMB) / 1024
+	for _, gsi := range t.GSIs {
+		storageGB += float64(gsi.IndexSizeMB) / 1024
+	}
+	return storageGB
+}
@@ -0,0 +1,195 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// capacitySide is the read or write half of a table or GSI.
+type capacitySide struct {
+	name        string
+	capacity    int64
+	scaling     *awsclient.AutoScalingSettings
+	consumed    []float64
+	provisioned []float64
+	events      float64
+	price       float64
+}
+
+// analyzeThrottling flags tables and GSIs that are throttled or regularly run
+// above HOT_UTILIZATION_PCT of their provisioned capacity. A throttled table
+// never keeps a cost-cutting recommendation: its savings are dropped and the
+// advice becomes a capacity increase or a switch to on-demand, whichever
+// costs less, with the extra monthly cost shown.
+func analyzeThrottling(t *awsclient.TableInfo) {
+	type target struct {
+		name  string
+		sides []capacitySide
+	}
+
+	inputs := capacityInputs(t)
+	targets := []target{{"table", capacitySides(inputs[0], t.ReadThrottleEvents, t.WriteThrottleEvents)}}
+	for i, gsi := range t.GSIs {
+		targets = append(targets, target{"GSI " + gsi.IndexName, capacitySides(inputs[i+1], gsi.ReadThrottleEvents, gsi.WriteThrottleEvents)})
+	}
+
+	var (
+		increases     []string
+		increaseExtra float64
+		throttled     = t.ThrottledRequests > 0
+	)
+
+	for i, tg := range targets {
+		hotPct := hotHoursPct(tg.sides...)
+		targetThrottled := hotPct > HOT_HOURS_PCT
+		for _, side := range tg.sides {
+			if side.events == 0 && hotHoursPct(side) <= HOT_HOURS_PCT {
+				continue
+			}
+			targetThrottled = true
+			units, extra := capacityIncrease(side)
+			switch {
+			case side.scaling != nil && units > int64(side.scaling.MaxCapacity):
+				increaseExtra += extra
+				increases = append(increases, fmt.Sprintf("%s %s autoscaling max %d→%d", tg.name, side.name, side.scaling.MaxCapacity, units))
+			case side.scaling == nil && side.capacity > 0 && units > side.capacity:
+				increaseExtra += extra
+				increases = append(increases, fmt.Sprintf("%s %s %d→%d", tg.name, side.name, side.capacity, units))
+			}
+		}
+
+		if i == 0 {
+			t.HotHoursPct = stats.Round(hotPct, 1)
+		} else {
+			t.GSIs[i-1].HotHoursPct = stats.Round(hotPct, 1)
+			t.GSIs[i-1].IsThrottled = targetThrottled
+		}
+		throttled = throttled || targetThrottled
+	}
+
+	t.IsThrottled = throttled
+	if !throttled {
+		return
+	}
+
+	if t.BillingMode != "PROVISIONED" {
+		t.ThrottlingRecommendation = "🔥 Throttled on PAY_PER_REQUEST: check for hot partition keys and on-demand maximum throughput"
+	} else {
+		current, _ := monthlyThroughputCost(t)
+		onDemandExtra := monthlyOnDemandCost(t) - current
+
+		if len(increases) > 0 && increaseExtra <= onDemandExtra {
+			t.ThrottlingExtraCost = stats.Round(increaseExtra, 2)
+			t.ThrottlingRecommendation = fmt.Sprintf("🔥 Under-provisioned: increase %s (%s)", strings.Join(increases, ", "), extraCostText(increaseExtra))
+		} else {
+			t.ThrottlingExtraCost = stats.Round(onDemandExtra, 2)
+			t.ThrottlingRecommendation = fmt.Sprintf("🔥 Under-provisioned: switch to PAY_PER_REQUEST (%s)", extraCostText(onDemandExtra))
+		}
+	}
+
+	// performance first: do not report savings on a throttled table
+	t.Recommendation = t.ThrottlingRecommendation
+	t.NeedOptimisation = true
+	t.PotentialSavings = 0
+	t.PotentialSavingsP = 0
+}
+
+func capacitySides(in capacityInput, readEvents, writeEvents float64) []capacitySide {
+	return []capacitySide{
+		{"read", in.readCap, in.readScaling, in.consumedRead, in.provisionedRead, readEvents, rcuPrice},
+		{"write", in.writeCap, in.writeScaling, in.consumedWrite, in.provisionedWrite, writeEvents, wcuPrice},
+	}
+}
+
+// hotHoursPct is the share of hours in which any of the sides consumed more
+// than HOT_UTILIZATION_PCT of its provisioned capacity. Autoscaling keeps
+// capacity close to its target on its own, so an autoscaled side only runs
+// hot once consumption reaches its max capacity.
+func hotHoursPct(sides ...capacitySide) float64 {
+	var hours, hot int
+	for _, side := range sides {
+		hours = max(hours, len(side.consumed))
+	}
+	if hours == 0 {
+		return 0
+	}
+
+	for h := 0; h < hours; h++ {
+		for _, side := range sides {
+			if h >= len(side.consumed) {
+				continue
+			}
+			if side.scaling != nil {
+				if side.scaling.MaxCapacity > 0 && side.consumed[h] >= float64(side.scaling.MaxCapacity) {
+					hot++
+					break
+				}
+				continue
+			}
+			provisioned := float64(side.capacity)
+			if h < len(side.provisioned) {
+				provisioned = side.provisioned[h]
+			}
+			if provisioned > 0 && side.consumed[h] > provisioned*HOT_UTILIZATION_PCT/100 {
+				hot++
+				break
+			}
+		}
+	}
+	return 100 * float64(hot) / float64(hours)
+}
+
+// capacityIncrease returns the capacity (or autoscaling max) that keeps the
+// side below HOT_UTILIZATION_PCT and what it adds per month. An autoscaling
+// max that already covers the peak is returned unchanged at no extra cost.
+func capacityIncrease(side capacitySide) (int64, float64) {
+	if len(side.consumed) == 0 {
+		return side.capacity, 0
+	}
+
+	if side.scaling != nil {
+		target := side.scaling.TargetUtilization / 100
+		if target == 0 {
+			target = recommendedTargetUtilization / 100
+		}
+		units := int64(math.Ceil(stats.Percentile(side.consumed, 100) / target))
+		if units <= int64(side.scaling.MaxCapacity) {
+			return int64(side.scaling.MaxCapacity), 0
+		}
+
+		// capacity autoscaling would have added in the hours it was capped
+		var missing float64
+		for h, c := range side.consumed {
+			provisioned := float64(side.capacity)
+			if h < len(side.provisioned) {
+				provisioned = side.provisioned[h]
+			}
+			missing += max(0, c/target-provisioned)
+		}
+		return units, missing * side.price * hoursPerMonth / float64(len(side.consumed))
+	}
+
+	units := max(side.capacity, int64(math.Ceil(stats.Percentile(side.consumed, 99)/(HOT_UTILIZATION_PCT/100.0))))
+	return units, float64(units-side.capacity) * side.price * hoursPerMonth
+}
+
+// monthlyOnDemandCost prices the consumed capacity of a table and its GSIs at
+// on-demand request rates.
+func monthlyOnDemandCost(t *awsclient.TableInfo) float64 {
+	var cost float64
+	for _, in := range capacityInputs(t) {
+		scale := hoursPerMonth / in.hours
+		cost += requestUnits(in.consumedRead, in.avgRead, in.hours)*scale*rruPrice + requestUnits(in.consumedWrite, in.avgWrite, in.hours)*scale*wruPrice
+	}
+	return cost
+}
+
+func extraCostText(extra float64) string {
+	if extra < 0 {
+		return fmt.Sprintf("saves $%.2f/month", -extra)
+	}
+	return fmt.Sprintf("+$%.2f/month", extra)
+}
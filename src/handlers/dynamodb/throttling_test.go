@@ -0,0 +1,138 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"math"
+	"testing"
+)
+
+func TestHotHoursPct(t *testing.T) {
+	tests := []struct {
+		name  string
+		sides []capacitySide
+		want  float64
+	}{
+		{
+			name:  "no data",
+			sides: []capacitySide{{capacity: 100}},
+			want:  0,
+		},
+		{
+			name:  "fixed capacity",
+			sides: []capacitySide{{capacity: 100, consumed: []float64{50, 90, 85, 10}}},
+			want:  50,
+		},
+		{
+			name:  "provisioned series overrides capacity",
+			sides: []capacitySide{{capacity: 100, consumed: []float64{90, 170}, provisioned: []float64{200, 200}}},
+			want:  50,
+		},
+		{
+			name: "hour counted once across sides",
+			sides: []capacitySide{
+				{capacity: 100, consumed: []float64{90, 0}},
+				{capacity: 100, consumed: []float64{90, 0}},
+			},
+			want: 50,
+		},
+		{
+			name: "autoscaled below max",
+			sides: []capacitySide{{
+				scaling:     &awsclient.AutoScalingSettings{MaxCapacity: 500},
+				consumed:    []float64{60, 90},
+				provisioned: []float64{70, 100},
+			}},
+			want: 0,
+		},
+		{
+			name: "autoscaled at max",
+			sides: []capacitySide{{
+				scaling:     &awsclient.AutoScalingSettings{MaxCapacity: 100},
+				consumed:    []float64{90, 100},
+				provisioned: []float64{100, 100},
+			}},
+			want: 50,
+		},
+		{
+			name:  "no capacity",
+			sides: []capacitySide{{consumed: []float64{5, 5}}},
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hotHoursPct(tt.sides...); got != tt.want {
+				t.Errorf("hotHoursPct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapacityIncrease(t *testing.T) {
+	tests := []struct {
+		name      string
+		side      capacitySide
+		wantUnits int64
+		wantExtra float64
+	}{
+		{
+			name:      "no data",
+			side:      capacitySide{capacity: 10, price: rcuPrice},
+			wantUnits: 10,
+		},
+		{
+			name:      "fixed capacity below the hot threshold",
+			side:      capacitySide{capacity: 100, consumed: []float64{10, 20}, price: rcuPrice},
+			wantUnits: 100,
+		},
+		{
+			name:      "fixed capacity raised to cover p99",
+			side:      capacitySide{capacity: 10, consumed: []float64{8, 16}, price: rcuPrice},
+			wantUnits: 20,
+			wantExtra: 10 * rcuPrice * hoursPerMonth,
+		},
+		{
+			name: "autoscaled max raised to cover the peak",
+			side: capacitySide{
+				scaling:     &awsclient.AutoScalingSettings{MaxCapacity: 200, TargetUtilization: 50},
+				consumed:    []float64{100, 150},
+				provisioned: []float64{200, 200},
+				price:       rcuPrice,
+			},
+			wantUnits: 300,
+			wantExtra: 100 * rcuPrice * hoursPerMonth / 2,
+		},
+		{
+			name: "autoscaled max already covers the peak",
+			side: capacitySide{
+				scaling:     &awsclient.AutoScalingSettings{MaxCapacity: 1000, TargetUtilization: 50},
+				consumed:    []float64{100, 150},
+				provisioned: []float64{200, 200},
+				price:       rcuPrice,
+			},
+			wantUnits: 1000,
+		},
+		{
+			name: "autoscaled default target",
+			side: capacitySide{
+				scaling:     &awsclient.AutoScalingSettings{MaxCapacity: 100},
+				consumed:    []float64{140},
+				provisioned: []float64{100},
+				price:       rcuPrice,
+			},
+			wantUnits: 200,
+			wantExtra: 100 * rcuPrice * hoursPerMonth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			units, extra := capacityIncrease(tt.side)
+			if units != tt.wantUnits {
+				t.Errorf("capacityIncrease() units = %d, want %d", units, tt.wantUnits)
+			}
+			if math.Abs(extra-tt.wantExtra) > 1e-9 {
+				t.Errorf("capacityIncrease() extra = %v, want %v", extra, tt.wantExtra)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package dynamodb
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"time"
+)
+
+const (
+	idleActionDelete       = "delete"
+	idleActionBackupDelete = "back up then delete"
+	idleActionOnDemand     = "switch to on-demand"
+)
+
+// analyzeIdleTable flags tables with zero or near-zero consumed capacity over
+// the lookback window and suggests what to do with them. Tables created inside
+// the window are left alone, they may not have received traffic yet.
+func analyzeIdleTable(t *awsclient.TableInfo) {
+	if t.TableName == "" || !usageSeriesAvailable(t) {
+		return
+	}
+
+	hours := lookbackHours(t)
+	lookbackStart := time.Now().Add(-time.Duration(hours) * time.Hour)
+	if t.CreationDateTime != nil && t.CreationDateTime.After(lookbackStart) {
+		return
+	}
+
+	// CloudWatch emits no consumed-capacity datapoints without traffic, so
+	// the scan stores those windows as zeros; failed fetches were skipped above
+	var requestUnits float64
+	for _, in := range capacityInputs(t) {
+		requestUnits += sum(in.consumedRead)*3600 + sum(in.consumedWrite)*3600
+	}
+	if requestUnits >= IDLE_MAX_REQUEST_UNITS {
+		return
+	}
+
+	throughputCost, iaThroughputCost := monthlyThroughputCost(t)
+	storageGB := tableStorageGB(t)
+	storageCost := storageGB * storagePrice
+	if t.TableClass == tableClassIA {
+		throughputCost = iaThroughputCost
+		storageCost = storageGB * iaStoragePrice
+	}
+	monthlyCost := throughputCost + storageCost
+
+	t.IsIdle = true
+	t.IdleReason = idleReason(t, requestUnits)
+
+	switch {
+	case requestUnits == 0 && t.ItemCount == 0:
+		t.IdleAction = idleActionDelete
+		t.IdleMonthlyCostAvoided = stats.Round(monthlyCost, 2)
+	case requestUnits == 0 || t.BillingMode != "PROVISIONED":
+		t.IdleAction = idleActionBackupDelete
+		t.IdleMonthlyCostAvoided = stats.Round(max(0, monthlyCost-storageGB*backupStoragePrice), 2)
+	default:
+		// near-zero traffic on provisioned capacity: pay per request instead
+		scale := hoursPerMonth / hours
+		onDemand := requestUnits * scale * rruPrice
+		t.IdleAction = idleActionOnDemand
+		t.IdleMonthlyCostAvoided = stats.Round(max(0, throughputCost-onDemand), 2)
+	}
+}
+
+func idleReason(t *awsclient.TableInfo, requestUnits float64) string {
+	reason := fmt.Sprintf("%.0f request units in %.0f days, %d items", requestUnits, lookbackHours(t)/24, t.ItemCount)
+	if t.LastConsumedAt != nil {
+		reason += ", last activity " + t.LastConsumedAt.Format(time.DateOnly)
+	} else {
+		reason += ", no activity in window"
+	}
+	if t.LastIncreaseDateTime != nil || t.LastDecreaseDateTime != nil {
+		last := t.LastIncreaseDateTime
+		if last == nil || (t.LastDecreaseDateTime != nil && t.LastDecreaseDateTime.After(*last)) {
+			last = t.LastDecreaseDateTime
+		}
+		reason += ", capacity last changed " + last.Format(time.DateOnly)
+	}
+	return reason
+}
+
+// usageSeriesAvailable reports whether the consumed capacity of the table and
+// every GSI was read, so a failed CloudWatch call is not taken for no traffic.
+func usageSeriesAvailable(t *awsclient.TableInfo) bool {
+	if !t.UsageSeriesAvailable {
+		return false
+	}
+	for _, g := range t.GSIs {
+		if !g.UsageSeriesAvailable {
+			return false
+		}
+	}
+	return true
+}
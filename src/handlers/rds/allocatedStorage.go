@@ -0,0 +1,94 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"math"
+)
+
+const bytesPerGB = 1024 * 1024 * 1024
+
+// analyzeAllocatedStorage tracks used versus allocated storage and its growth
+// over the lookback window. Volumes that fill up within
+// STORAGE_FULL_WARNING_DAYS get a warning; volumes whose free space never
+// drops below STORAGE_MAX_FREE_PCT get a smaller target size. RDS can not
+// shrink a volume in place, so the advice is to migrate (Blue/Green or
+// dump and restore). DaysUntilFull stays -1 unless the volume is growing.
+func analyzeAllocatedStorage(ti *awsclient.RDSInfo) {
+	ti.DaysUntilFull = -1
+	if awsclient.IsAurora(ti.Engine) || ti.AllocatedStorageGB == 0 || len(ti.FreeStorageSeries) == 0 {
+		return
+	}
+
+	allocated := float64(ti.AllocatedStorageGB)
+	used := make([]float64, len(ti.FreeStorageSeries))
+	for i, free := range ti.FreeStorageSeries {
+		used[i] = allocated - free/bytesPerGB
+	}
+	ti.StorageGrowthGBPerDay = stats.Round(slope(used)*24, 2)
+
+	limit := allocated
+	if ti.StorageAutoscaling {
+		limit = float64(ti.MaxAllocatedStorageGB)
+	}
+	if ti.StorageGrowthGBPerDay > 0 {
+		ti.DaysUntilFull = stats.Round(math.Max(0, limit-ti.UsedStorageGB)/ti.StorageGrowthGBPerDay, 0)
+	}
+
+	if ti.DaysUntilFull >= 0 && ti.DaysUntilFull < STORAGE_FULL_WARNING_DAYS {
+		advice := "raise MaxAllocatedStorage"
+		if !ti.StorageAutoscaling {
+			advice = "enable storage autoscaling or increase allocated storage"
+		}
+		ti.StorageRecommendation = fmt.Sprintf("⚠️ Storage full in ~%.0f days at %.1f GB/day: %s", ti.DaysUntilFull, ti.StorageGrowthGBPerDay, advice)
+		addRecommendation(ti, ti.StorageRecommendation)
+		return
+	}
+
+	minFreePct := 100 * stats.Percentile(ti.FreeStorageSeries, 0) / bytesPerGB / allocated
+	if minFreePct < STORAGE_MAX_FREE_PCT {
+		return
+	}
+
+	growth := math.Max(0, ti.StorageGrowthGBPerDay) * STORAGE_GROWTH_DAYS
+	target := int64(math.Ceil((ti.UsedStorageGB + growth) * (1 + STORAGE_HEADROOM_PCT/100.0)))
+	target = max(target, STORAGE_MIN_GB)
+	if target >= ti.AllocatedStorageGB {
+		return
+	}
+
+	alt := *ti
+	alt.AllocatedStorageGB = target
+	smaller := awsclient.EstimateRDSCost(alt, ti.Region)
+	savings := ti.StorageMonthlyCost + ti.IOPSMonthlyCost + ti.ThroughputMonthlyCost - (smaller.Storage + smaller.IOPS + smaller.Throughput)
+	if savings <= 0 {
+		return
+	}
+
+	ti.RecommendedVolumeGB = target
+	ti.StorageRightSizingSavings = stats.Round(savings, 2)
+	ti.StorageRecommendation = fmt.Sprintf("📦 %.0f%% of %d GB free: migrate to a %d GB volume (%s)", ti.FreeStoragePct, ti.AllocatedStorageGB, target, savingsText(savings))
+	if ti.StorageAutoscaling {
+		ti.StorageRecommendation += " and keep storage autoscaling on"
+	}
+	addRecommendation(ti, ti.StorageRecommendation)
+	ti.PotentialSavings += ti.StorageRightSizingSavings
+}
+
+// slope is the least-squares change per hour of an hourly series.
+func slope(series []float64) float64 {
+	n := float64(len(series))
+	if n < 2 {
+		return 0
+	}
+	var sx, sy, sxy, sxx float64
+	for i, y := range series {
+		x := float64(i)
+		sx += x
+		sy += y
+		sxy += x * y
+		sxx += x * x
+	}
+	return (n*sxy - sx*sy) / (n*sxx - sx*sx)
+}
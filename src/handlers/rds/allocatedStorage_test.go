@@ -0,0 +1,77 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"math"
+	"testing"
+)
+
+func TestSlope(t *testing.T) {
+	tests := []struct {
+		name   string
+		series []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"single point", []float64{5}, 0},
+		{"flat", []float64{3, 3, 3}, 0},
+		{"rising", []float64{1, 3, 5, 7}, 2},
+		{"falling", []float64{10, 9, 8}, -1},
+		{"noisy", []float64{0, 2, 1, 3}, 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slope(tt.series); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("slope(%v) = %v, want %v", tt.series, got, tt.want)
+			}
+		})
+	}
+}
+
+// freeSeries returns hourly free-storage samples in bytes, starting at
+// startGB and changing by stepGB every hour.
+func freeSeries(startGB, stepGB float64, hours int) []float64 {
+	series := make([]float64, hours)
+	for h := range series {
+		series[h] = (startGB + stepGB*float64(h)) * bytesPerGB
+	}
+	return series
+}
+
+func TestDaysUntilFull(t *testing.T) {
+	tests := []struct {
+		name        string
+		autoscaling bool
+		free        []float64
+		wantGrowth  float64
+		wantDays    float64
+	}{
+		{"filling up", false, freeSeries(60, -1, 11), 24, 2},
+		{"autoscaling raises the limit", true, freeSeries(60, -1, 11), 24, 40},
+		{"flat", false, freeSeries(50, 0, 11), 0, -1},
+		{"shrinking", false, freeSeries(40, 1, 11), -24, -1},
+		{"no free storage series", false, nil, 0, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := awsclient.RDSInfo{
+				Engine:                "postgres",
+				StorageType:           "gp3",
+				AllocatedStorageGB:    100,
+				MaxAllocatedStorageGB: 1000,
+				StorageAutoscaling:    tt.autoscaling,
+				FreeStorageSeries:     tt.free,
+			}
+			if len(tt.free) > 0 {
+				ti.UsedStorageGB = 100 - tt.free[len(tt.free)-1]/bytesPerGB
+			}
+			analyzeAllocatedStorage(&ti)
+			if ti.StorageGrowthGBPerDay != tt.wantGrowth {
+				t.Errorf("StorageGrowthGBPerDay = %v, want %v", ti.StorageGrowthGBPerDay, tt.wantGrowth)
+			}
+			if ti.DaysUntilFull != tt.wantDays {
+				t.Errorf("DaysUntilFull = %v, want %v", ti.DaysUntilFull, tt.wantDays)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package rds
+
+import (
+	"context"
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"log"
+	"sync"
+	"time"
+
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// extractAuroraClusters describes the Aurora clusters of the region and prices
+// them: storage and I/O at cluster level from VolumeBytesUsed and
+// VolumeRead/WriteIOPs, compute from the member instances already scanned.
+func extractAuroraClusters(ctx context.Context, client *awsclient.AWSClient, instances []awsclient.RDSInfo) []awsclient.AuroraClusterInfo {
+	log.Println("Fetching Aurora cluster metadata...")
+
+	byID := map[string]*awsclient.RDSInfo{}
+	for i := range instances {
+		byID[instances[i].InstanceID] = &instances[i]
+	}
+
+	wg := sync.WaitGroup{}
+	ch := make(chan awsclient.AuroraClusterInfo)
+	clusters := []awsclient.AuroraClusterInfo{}
+
+	for _, cluster := range client.GetAuroraClusters(ctx) {
+		wg.Go(func() {
+			ch <- processAuroraCluster(ctx, client, awsclient.NewAuroraClusterInfo(cluster))
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+
+	for info := range ch {
+		addClusterInstances(&info, byID)
+		clusters = append(clusters, info)
+	}
+
+	return clusters
+}
+
+func processAuroraCluster(ctx context.Context, client *awsclient.AWSClient, info awsclient.AuroraClusterInfo) awsclient.AuroraClusterInfo {
+	hours := 24 * TIMEFRAME
+	start := time.Now().Add(-time.Duration(hours) * time.Hour)
+	info.Region = client.Region
+
+	volume, _ := awsclient.GetRDSClusterSeries(ctx, client.CloudWatch, info.ClusterID, "VolumeBytesUsed", cwtypes.StatisticAverage, start, hours)
+	reads, _ := awsclient.GetRDSClusterSeries(ctx, client.CloudWatch, info.ClusterID, "VolumeReadIOPs", cwtypes.StatisticSum, start, hours)
+	writes, _ := awsclient.GetRDSClusterSeries(ctx, client.CloudWatch, info.ClusterID, "VolumeWriteIOPs", cwtypes.StatisticSum, start, hours)
+	backup, _ := awsclient.GetRDSClusterSeries(ctx, client.CloudWatch, info.ClusterID, "TotalBackupStorageBilled", cwtypes.StatisticAverage, start, hours)
+
+	if volume = awsclient.ForwardFill(volume); len(volume) > 0 {
+		info.StorageGB = stats.Round(volume[len(volume)-1]/bytesPerGB, 2)
+	}
+	if backup = awsclient.ForwardFill(backup); len(backup) > 0 {
+		info.BackupStorageBilledGB = stats.Round(backup[len(backup)-1]/bytesPerGB, 2)
+	}
+
+	scale := hoursPerMonth / float64(hours)
+	info.ReadIOsPerMonth = stats.Round(sum(awsclient.ZeroFill(reads))*scale, 0)
+	info.WriteIOsPerMonth = stats.Round(sum(awsclient.ZeroFill(writes))*scale, 0)
+
+	info.StorageMonthlyCost, info.IOMonthlyCost = awsclient.AuroraStorageCost(info.StorageType, info.StorageGB, info.ReadIOsPerMonth+info.WriteIOsPerMonth, info.Region)
+	info.BackupMonthlyCost = stats.Round(awsclient.RDSBackupStorageCost(info.BackupStorageBilledGB, info.Region), 2)
+	return info
+}
+
+// addClusterInstances links the member instances to the cluster and rolls
+// their compute cost up into the cluster total.
+func addClusterInstances(info *awsclient.AuroraClusterInfo, instances map[string]*awsclient.RDSInfo) {
+	members := append([]string{info.WriterID}, info.ReaderIDs...)
+	for _, id := range members {
+		inst, ok := instances[id]
+		if id == "" || !ok {
+			continue
+		}
+		role := "reader"
+		if id == info.WriterID {
+			role = "writer"
+		}
+		inst.ClusterRole = role
+		inst.MinACU = info.ServerlessMinACU
+		inst.MaxACU = info.ServerlessMaxACU
+		info.Instances = append(info.Instances, awsclient.AuroraInstanceCost{
+			InstanceID:         id,
+			InstanceClass:      inst.InstanceClass,
+			Role:               role,
+			ComputeMonthlyCost: inst.ComputeMonthlyCost,
+		})
+		info.InstancesMonthlyCost += inst.ComputeMonthlyCost
+	}
+
+	info.InstancesMonthlyCost = stats.Round(info.InstancesMonthlyCost, 2)
+	info.TotalMonthlyCost = stats.Round(info.InstancesMonthlyCost+info.StorageMonthlyCost+info.IOMonthlyCost+info.BackupMonthlyCost, 2)
+}
+
+func sum(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,67 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+)
+
+const (
+	auroraStandard    = "aurora"
+	auroraIOOptimized = "aurora-iopt1"
+)
+
+// analyzeAuroraStorageConfig prices a cluster under Aurora Standard (storage
+// plus per-request I/O) and Aurora I/O-Optimized (higher instance and storage
+// rates, no I/O charges) from its observed volume I/O. Standard clusters whose
+// I/O is more than AURORA_IO_OPTIMIZED_SHARE_PCT of spend are moved to
+// I/O-Optimized; I/O-Optimized clusters that would be cheaper on Standard are
+// moved back.
+func analyzeAuroraStorageConfig(c *awsclient.AuroraClusterInfo) {
+	if c.Status != "available" || c.EngineMode == "serverless" {
+		return // Serverless v1 has no I/O-Optimized option
+	}
+
+	compute := c.InstancesMonthlyCost
+	if c.StorageType == auroraIOOptimized {
+		compute /= awsclient.AuroraIOOptimizedFactor
+	}
+	ios := c.ReadIOsPerMonth + c.WriteIOsPerMonth
+
+	stdStorage, stdIO := awsclient.AuroraStorageCost(auroraStandard, c.StorageGB, ios, c.Region)
+	optStorage, _ := awsclient.AuroraStorageCost(auroraIOOptimized, c.StorageGB, ios, c.Region)
+
+	c.StandardMonthlyCost = stats.Round(compute+stdStorage+stdIO+c.BackupMonthlyCost, 2)
+	c.IOOptimizedMonthlyCost = stats.Round(compute*awsclient.AuroraIOOptimizedFactor+optStorage+c.BackupMonthlyCost, 2)
+	if c.StandardMonthlyCost > 0 {
+		c.IOSharePct = stats.Round(100*stdIO/c.StandardMonthlyCost, 1)
+	}
+
+	var savings float64
+	switch {
+	case c.StorageType != auroraIOOptimized && c.IOSharePct > AURORA_IO_OPTIMIZED_SHARE_PCT:
+		savings = c.StandardMonthlyCost - c.IOOptimizedMonthlyCost
+		c.RecommendedStorageType = auroraIOOptimized
+	case c.StorageType == auroraIOOptimized && c.StandardMonthlyCost < c.IOOptimizedMonthlyCost:
+		savings = c.IOOptimizedMonthlyCost - c.StandardMonthlyCost
+		c.RecommendedStorageType = auroraStandard
+	}
+	if savings <= 0 {
+		c.RecommendedStorageType = ""
+		return
+	}
+
+	c.StorageConfigSavings = stats.Round(savings, 2)
+	c.StorageConfigRecommendation = fmt.Sprintf("⚡ Switch to %s: I/O is %.0f%% of spend (Standard $%.2f vs I/O-Optimized $%.2f per month, saves $%.2f/month)",
+		storageConfigName(c.RecommendedStorageType), c.IOSharePct, c.StandardMonthlyCost, c.IOOptimizedMonthlyCost, savings)
+	c.Recommendation = c.StorageConfigRecommendation
+	c.NeedOptimisation = true
+	c.PotentialSavings += c.StorageConfigSavings
+}
+
+func storageConfigName(storageType string) string {
+	if storageType == auroraIOOptimized {
+		return "Aurora I/O-Optimized"
+	}
+	return "Aurora Standard"
+}
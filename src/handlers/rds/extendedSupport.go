@@ -0,0 +1,115 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	supportStandard = "standard"
+	supportUpcoming = "upcoming"
+	supportExtended = "extended"
+	supportEnded    = "extended support ended"
+)
+
+// UpgradePriority is an instance's Extended Support exposure over the next
+// twelve months, used to order engine upgrades.
+type UpgradePriority struct {
+	InstanceID         string    `json:"instanceId"`
+	ClusterID          string    `json:"clusterId"`
+	Engine             string    `json:"engine"`
+	EngineVersion      string    `json:"engineVersion"`
+	UpgradeTo          string    `json:"upgradeTo"`
+	VCPU               int       `json:"vcpu"`
+	Status             string    `json:"status"`
+	StandardSupportEnd time.Time `json:"standardSupportEnd"`
+	MonthlyCost        float64   `json:"monthlyCost"`
+	AnnualExposure     float64   `json:"annualExposure"`
+}
+
+// analyzeExtendedSupport prices the RDS Extended Support charge of an
+// instance from the bundled lifecycle calendar: the charge it pays now and
+// what the next twelve months add up to, which covers versions leaving
+// standard support or entering the year-3 rate. Multi-AZ standbys are billed
+// too. Serverless instances are billed per ACU and are not modeled, and
+// replicas flagged for removal already count their whole cost.
+func analyzeExtendedSupport(ti *awsclient.RDSInfo, now time.Time) {
+	if ti.ReplicaAction == replicaActionRemove {
+		return
+	}
+	lifecycle, ok := awsclient.GetEngineLifecycle(ti.Engine, ti.EngineVersion)
+	if !ok {
+		return
+	}
+	spec, ok := awsclient.GetRDSInstanceSpec(ti.InstanceClass)
+	if !ok {
+		return
+	}
+	end := lifecycle.StandardSupportEnd
+	ti.StandardSupportEnd = &end
+
+	vcpuHours := float64(spec.VCPU) * hoursPerMonth
+	if ti.Deployment == awsclient.DeploymentMultiAZ {
+		vcpuHours *= 2
+	}
+
+	ti.ExtendedSupportMonthlyCost = stats.Round(awsclient.ExtendedSupportRate(lifecycle, now, ti.Region)*vcpuHours, 2)
+	var exposure float64
+	for m := 0; m < 12; m++ {
+		exposure += awsclient.ExtendedSupportRate(lifecycle, now.AddDate(0, m, 0), ti.Region) * vcpuHours
+	}
+	ti.ExtendedSupportAnnualExposure = stats.Round(exposure, 2)
+
+	switch {
+	case !now.Before(lifecycle.ExtendedSupportEnd):
+		ti.ExtendedSupportStatus = supportEnded
+		ti.ExtendedSupportRecommendation = fmt.Sprintf("🚨 %s %s is past the end of Extended Support (%s): AWS upgrades it automatically, upgrade to %s now",
+			ti.Engine, ti.EngineVersion, lifecycle.ExtendedSupportEnd.Format(time.DateOnly), lifecycle.UpgradeTo)
+	case ti.ExtendedSupportMonthlyCost > 0:
+		ti.ExtendedSupportStatus = supportExtended
+		ti.ExtendedSupportRecommendation = fmt.Sprintf("🕰️ %s %s is on Extended Support since %s: upgrade to %s, saves $%.2f/month",
+			ti.Engine, ti.EngineVersion, end.Format(time.DateOnly), lifecycle.UpgradeTo, ti.ExtendedSupportMonthlyCost)
+		ti.PotentialSavings += ti.ExtendedSupportMonthlyCost
+	case exposure > 0:
+		ti.ExtendedSupportStatus = supportUpcoming
+		ti.ExtendedSupportRecommendation = fmt.Sprintf("🕰️ %s %s leaves standard support on %s: upgrade to %s to avoid $%.2f in the next 12 months",
+			ti.Engine, ti.EngineVersion, end.Format(time.DateOnly), lifecycle.UpgradeTo, exposure)
+	default:
+		ti.ExtendedSupportStatus = supportStandard
+		return
+	}
+	addRecommendation(ti, ti.ExtendedSupportRecommendation)
+}
+
+// upgradePriorities lists the instances with Extended Support exposure, the
+// most expensive first.
+func upgradePriorities(instances []awsclient.RDSInfo) []UpgradePriority {
+	priorities := []UpgradePriority{}
+	for _, ti := range instances {
+		if ti.StandardSupportEnd == nil || ti.ExtendedSupportStatus == supportStandard {
+			continue
+		}
+		lifecycle, _ := awsclient.GetEngineLifecycle(ti.Engine, ti.EngineVersion)
+		spec, _ := awsclient.GetRDSInstanceSpec(ti.InstanceClass)
+		priorities = append(priorities, UpgradePriority{
+			InstanceID:         ti.InstanceID,
+			ClusterID:          ti.ClusterID,
+			Engine:             ti.Engine,
+			EngineVersion:      ti.EngineVersion,
+			UpgradeTo:          lifecycle.UpgradeTo,
+			VCPU:               spec.VCPU,
+			Status:             ti.ExtendedSupportStatus,
+			StandardSupportEnd: *ti.StandardSupportEnd,
+			MonthlyCost:        ti.ExtendedSupportMonthlyCost,
+			AnnualExposure:     ti.ExtendedSupportAnnualExposure,
+		})
+	}
+
+	sort.Slice(priorities, func(i, j int) bool {
+		return priorities[i].AnnualExposure > priorities[j].AnnualExposure
+	})
+	return priorities
+}
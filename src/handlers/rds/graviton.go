@@ -0,0 +1,158 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// x86 families and their Graviton equivalents
+var gravitonFamilies = map[string][]string{
+	"m4":  {"m6g", "m7g"},
+	"m5":  {"m6g", "m7g"},
+	"m5d": {"m6gd"},
+	"m6i": {"m6g", "m7g"},
+	"m7i": {"m7g"},
+	"r4":  {"r6g", "r7g"},
+	"r5":  {"r6g", "r7g"},
+	"r5d": {"r6gd"},
+	"r6i": {"r6g", "r7g"},
+	"r7i": {"r7g"},
+	"t2":  {"t4g"},
+	"t3":  {"t4g"},
+}
+
+// minimum engine versions supporting each Graviton generation; engines not
+// listed (Oracle, SQL Server, Db2) do not run on Graviton
+var gravitonMinVersions = map[string]map[string]string{
+	"mysql":             {"6g": "8.0.17", "7g": "8.0.28"},
+	"mariadb":           {"6g": "10.4.13", "7g": "10.6.10"},
+	"postgres":          {"6g": "12.3", "7g": "13.4"},
+	"aurora-mysql":      {"6g": "2.09.2", "7g": "3.01.0"},
+	"aurora-postgresql": {"6g": "11.9", "7g": "13.10"},
+}
+
+// analyzeGraviton maps an x86 instance to the cheapest Graviton class of the
+// same size its engine supports. The right-sized class is used as the
+// starting point when there is one, so the savings add up. Instances whose
+// engine version is too old are flagged as needing an upgrade first.
+func analyzeGraviton(ti *awsclient.RDSInfo) {
+	minVersions, ok := gravitonMinVersions[ti.Engine]
+	if !ok {
+		return
+	}
+
+	class := ti.InstanceClass
+	if ti.RecommendedClass != "" {
+		class = ti.RecommendedClass
+	}
+	family, size, ok := awsclient.ParseRDSClass(class)
+	if !ok {
+		return
+	}
+
+	base := *ti
+	base.InstanceClass = class
+	baseCost := awsclient.EstimateRDSCost(base, ti.Region).Compute
+
+	var (
+		best        string
+		bestSavings float64
+		upgrade     string // lowest version that unlocks a Graviton class
+	)
+	version := engineVersion(ti.Engine, ti.EngineVersion)
+	for _, g := range gravitonFamilies[family] {
+		target := "db." + g + "." + size
+		if _, priced := awsclient.RDSHourlyRate(target, ti.Engine, ti.LicenseModel, ti.Region); !priced {
+			continue
+		}
+		minVersion := minVersions[gravitonGeneration(g)]
+		if compareVersions(version, minVersion) < 0 {
+			if upgrade == "" || compareVersions(minVersion, upgrade) < 0 {
+				upgrade = minVersion
+			}
+			continue
+		}
+
+		alt := base
+		alt.InstanceClass = target
+		savings := baseCost - awsclient.EstimateRDSCost(alt, ti.Region).Compute
+		if best == "" || savings > bestSavings {
+			best, bestSavings = target, savings
+		}
+	}
+
+	switch {
+	case best != "":
+		ti.GravitonClass = best
+		ti.GravitonSavings = stats.Round(bestSavings, 2)
+		ti.GravitonRecommendation = fmt.Sprintf("🦾 Move %s → %s (%s)", class, best, savingsText(bestSavings))
+		if bestSavings > 0 {
+			ti.PotentialSavings += ti.GravitonSavings
+		}
+	case upgrade != "":
+		ti.GravitonNeedsUpgrade = true
+		ti.GravitonMinEngineVersion = upgrade
+		ti.GravitonRecommendation = fmt.Sprintf("⬆️ Upgrade %s %s to %s or later before moving to Graviton", ti.Engine, ti.EngineVersion, upgrade)
+	default:
+		return
+	}
+
+	addRecommendation(ti, ti.GravitonRecommendation)
+}
+
+// gravitonGeneration returns "6g" for m6g/r6gd/t4g and "7g" for m7g/r7g.
+func gravitonGeneration(family string) string {
+	if family == "t4g" || strings.HasPrefix(family[1:], "6g") {
+		return "6g"
+	}
+	return "7g"
+}
+
+// engineVersion returns the comparable part of an engine version; Aurora
+// MySQL versions such as 8.0.mysql_aurora.3.04.0 are compared on the Aurora
+// release.
+func engineVersion(engine, version string) string {
+	if engine == "aurora-mysql" {
+		if i := strings.Index(version, "mysql_aurora."); i >= 0 {
+			return version[i+len("mysql_aurora."):]
+		}
+		if strings.HasPrefix(version, "5.6") {
+			return "1"
+		}
+	}
+	return version
+}
+
+// compareVersions compares dotted numeric versions, returning -1, 0 or 1.
+// Non-numeric suffixes such as "-R2" are ignored.
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = leadingInt(pa[i])
+		}
+		if i < len(pb) {
+			y = leadingInt(pb[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func leadingInt(s string) int {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(s[:end])
+	return n
+}
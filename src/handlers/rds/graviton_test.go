@@ -0,0 +1,42 @@
+package rds
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"8.0.28", "8.0.28", 0},
+		{"8.0.17", "8.0.28", -1},
+		{"10.4", "9.6", 1},
+		{"12", "12.0", 0},
+		{"12.1", "12", 1},
+		{"19.0.0.0.ru-2021-01.rur-2021-01.r1", "19.0.0.0", 0},
+		{"8.0.28-R2", "8.0.28", 0},
+		{"2.07.2", "2.10.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestEngineVersion(t *testing.T) {
+	tests := []struct {
+		engine, version string
+		want            string
+	}{
+		{"mysql", "8.0.28", "8.0.28"},
+		{"aurora-mysql", "5.7.mysql_aurora.2.07.2", "2.07.2"},
+		{"aurora-mysql", "8.0.mysql_aurora.3.02.0", "3.02.0"},
+		{"aurora-mysql", "5.6.10a", "1"},
+		{"aurora-postgresql", "13.4", "13.4"},
+	}
+	for _, tt := range tests {
+		if got := engineVersion(tt.engine, tt.version); got != tt.want {
+			t.Errorf("engineVersion(%q, %q) = %q, want %q", tt.engine, tt.version, got, tt.want)
+		}
+	}
+}
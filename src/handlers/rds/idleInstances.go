@@ -0,0 +1,86 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	idleActionDeleteReplica  = "delete read replica"
+	idleActionSnapshotDelete = "snapshot then delete"
+	idleActionStop           = "stop (restarts automatically after 7 days, schedule re-stops)"
+	idleActionStopCluster    = "stop the cluster (restarts automatically after 7 days, schedule re-stops)"
+	idleActionServerless     = "convert to Aurora Serverless v2 with 0 ACU minimum"
+)
+
+// minimum engine versions for Aurora Serverless v2 scale-to-zero, by major
+// version
+var scaleToZeroMinVersions = map[string]map[string]string{
+	"aurora-mysql":      {"8": "8.0.mysql_aurora.3.08.0"},
+	"aurora-postgresql": {"13": "13.15", "14": "14.12", "15": "15.7", "16": "16.3", "17": "17.4"},
+}
+
+// analyzeIdleInstance flags running instances with near-zero connections, CPU
+// and IOPS over the whole lookback window and suggests what to do with them.
+// Instances created inside the window are left alone. An idle instance's
+// recommendation and savings replace those of the other analyzers.
+func analyzeIdleInstance(ti *awsclient.RDSInfo) {
+	if ti.Status != "available" || !ti.MetricsAvailable || len(ti.CPUSeries) == 0 {
+		return
+	}
+	lookbackStart := time.Now().Add(-time.Duration(len(ti.CPUSeries)) * time.Hour)
+	if ti.CreateTime != nil && ti.CreateTime.After(lookbackStart) {
+		return
+	}
+
+	connections := stats.Percentile(ti.ConnectionsSeries, 95)
+	cpu := stats.Percentile(ti.CPUSeries, 95)
+	iops := stats.Percentile(ti.IOPSSeries, 95)
+	if connections > IDLE_MAX_CONNECTIONS || cpu > IDLE_MAX_CPU_PCT || iops > IDLE_MAX_IOPS {
+		return
+	}
+
+	ti.IsIdle = true
+	ti.IdleReason = fmt.Sprintf("p95 %.0f connections, %.1f%% CPU, %.0f IOPS in %d days", connections, cpu, iops, len(ti.CPUSeries)/24)
+
+	switch {
+	case ti.ReadReplicaSourceID != "":
+		ti.IdleAction = idleActionDeleteReplica
+		ti.IdleMonthlyCostAvoided = ti.EstimatedCost
+	case stats.Percentile(ti.ConnectionsSeries, 100) == 0 && !awsclient.IsAurora(ti.Engine):
+		snapshot := awsclient.RDSBackupStorageCost(ti.UsedStorageGB, ti.Region)
+		ti.IdleAction = idleActionSnapshotDelete
+		ti.IdleMonthlyCostAvoided = max(0, ti.EstimatedCost-snapshot)
+	case supportsScaleToZero(ti.Engine, ti.EngineVersion):
+		ti.IdleAction = idleActionServerless
+		ti.IdleMonthlyCostAvoided = ti.ComputeMonthlyCost
+	default:
+		// storage and provisioned IOPS are still billed while stopped
+		ti.IdleAction = idleActionStop
+		ti.IdleMonthlyCostAvoided = ti.ComputeMonthlyCost
+		if ti.Deployment == awsclient.DeploymentMultiAZCluster || ti.Deployment == awsclient.DeploymentAurora {
+			ti.IdleAction = idleActionStopCluster
+		}
+	}
+	ti.IdleMonthlyCostAvoided = stats.Round(ti.IdleMonthlyCostAvoided, 2)
+
+	ti.Recommendation = fmt.Sprintf("💤 Idle (%s): %s, saves $%.2f/month", ti.IdleReason, ti.IdleAction, ti.IdleMonthlyCostAvoided)
+	ti.NeedOptimisation = true
+	ti.PotentialSavings = ti.IdleMonthlyCostAvoided
+}
+
+func supportsScaleToZero(engine, version string) bool {
+	byMajor, ok := scaleToZeroMinVersions[engine]
+	if !ok {
+		return false
+	}
+	major, _, _ := strings.Cut(version, ".")
+	minVersion, ok := byMajor[major]
+	if !ok {
+		return false
+	}
+	return compareVersions(engineVersion(engine, version), engineVersion(engine, minVersion)) >= 0
+}
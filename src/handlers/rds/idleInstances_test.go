@@ -0,0 +1,104 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func hourly(value float64, hours int) []float64 {
+	series := make([]float64, hours)
+	for h := range series {
+		series[h] = value
+	}
+	return series
+}
+
+func TestAnalyzeIdleInstance(t *testing.T) {
+	hours := TIMEFRAME * 24
+	created := time.Now().AddDate(-1, 0, 0)
+	occasional := hourly(0, hours)
+	occasional[10] = 1
+
+	tests := []struct {
+		name        string
+		inst        rdstypes.DBInstance
+		cpu         []float64
+		connections []float64
+		wantIdle    bool
+		wantAction  string
+	}{
+		{
+			name:        "no connections",
+			inst:        rdstypes.DBInstance{DBInstanceStatus: aws.String("available"), InstanceCreateTime: &created},
+			cpu:         hourly(1, hours),
+			connections: hourly(0, hours),
+			wantIdle:    true,
+			wantAction:  idleActionSnapshotDelete,
+		},
+		{
+			name:        "occasional connection",
+			inst:        rdstypes.DBInstance{DBInstanceStatus: aws.String("available"), InstanceCreateTime: &created},
+			cpu:         hourly(1, hours),
+			connections: occasional,
+			wantIdle:    true,
+			wantAction:  idleActionStop,
+		},
+		{
+			name: "read replica",
+			inst: rdstypes.DBInstance{
+				DBInstanceStatus:                      aws.String("available"),
+				InstanceCreateTime:                    &created,
+				ReadReplicaSourceDBInstanceIdentifier: aws.String("primary"),
+			},
+			cpu:         hourly(1, hours),
+			connections: hourly(0, hours),
+			wantIdle:    true,
+			wantAction:  idleActionDeleteReplica,
+		},
+		{
+			name:        "busy",
+			inst:        rdstypes.DBInstance{DBInstanceStatus: aws.String("available"), InstanceCreateTime: &created},
+			cpu:         hourly(40, hours),
+			connections: hourly(20, hours),
+		},
+		{
+			name:        "stopped",
+			inst:        rdstypes.DBInstance{DBInstanceStatus: aws.String("stopped"), InstanceCreateTime: &created},
+			cpu:         hourly(0, hours),
+			connections: hourly(0, hours),
+		},
+		{
+			name:        "created inside the window",
+			inst:        rdstypes.DBInstance{DBInstanceStatus: aws.String("available"), InstanceCreateTime: aws.Time(time.Now().AddDate(0, 0, -2))},
+			cpu:         hourly(0, hours),
+			connections: hourly(0, hours),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.inst.DBInstanceIdentifier = aws.String("db-1")
+			tt.inst.Engine = aws.String("postgres")
+			tt.inst.DBInstanceClass = aws.String("db.m5.large")
+			ti := awsclient.NewRDSInfo(tt.inst)
+			ti.MetricsAvailable = true
+			ti.CPUSeries = tt.cpu
+			ti.ConnectionsSeries = tt.connections
+			ti.IOPSSeries = hourly(0, hours)
+
+			analyzeIdleInstance(&ti)
+			if ti.IsIdle != tt.wantIdle {
+				t.Fatalf("IsIdle = %v, want %v", ti.IsIdle, tt.wantIdle)
+			}
+			if ti.IdleAction != tt.wantAction {
+				t.Errorf("IdleAction = %q, want %q", ti.IdleAction, tt.wantAction)
+			}
+			if tt.wantIdle && !ti.NeedOptimisation {
+				t.Error("idle instance not flagged for optimisation")
+			}
+		})
+	}
+}
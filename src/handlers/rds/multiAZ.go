@@ -0,0 +1,119 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// name tokens and tag values that mark a database as non-production
+var nonProdTokens = map[string]bool{
+	"dev": true, "develop": true, "development": true,
+	"test": true, "testing": true,
+	"stage": true, "staging": true, "stg": true,
+}
+
+// tag keys that carry the environment
+var environmentTagKeys = []string{"env", "environment", "stage", "tier", "name"}
+
+// analyzeMultiAZ flags non-production instances running Multi-AZ, with the
+// savings of converting them to Single-AZ. The class right-sizing or Graviton
+// advice recommends is priced, so the saving adds to theirs. Replicas flagged
+// for removal already count their whole cost.
+func analyzeMultiAZ(ti *awsclient.RDSInfo) {
+	ti.IsNonProd = isNonProd(ti.InstanceID, ti.Tags)
+	if !ti.IsNonProd || ti.ReplicaAction == replicaActionRemove {
+		return
+	}
+
+	var savings float64
+	switch ti.Deployment {
+	case awsclient.DeploymentMultiAZ:
+		alt := *ti
+		alt.InstanceClass = firstNonEmpty(ti.GravitonClass, ti.RecommendedClass, ti.InstanceClass)
+		multi := awsclient.EstimateRDSCost(alt, ti.Region)
+		alt.MultiAZ = false
+		single := awsclient.EstimateRDSCost(alt, ti.Region)
+		savings = multi.Total - single.Total
+	case awsclient.DeploymentMultiAZCluster:
+		// a Multi-AZ DB cluster runs a writer and two standbys; each member
+		// carries a third of the two standbys' cost
+		savings = ti.EstimatedCost * 2 / 3
+	default:
+		return
+	}
+	if savings <= 0 {
+		return
+	}
+
+	ti.MultiAZSavings = stats.Round(savings, 2)
+	ti.MultiAZRecommendation = fmt.Sprintf("🧪 Non-production %s runs %s: convert to Single-AZ, saves $%.2f/month", ti.InstanceID, ti.Deployment, ti.MultiAZSavings)
+	addRecommendation(ti, ti.MultiAZRecommendation)
+	ti.PotentialSavings += ti.MultiAZSavings
+}
+
+// analyzeClusterMultiAZ flags non-production Aurora clusters that keep
+// readers in other AZs. Dropping them saves the readers' compute, except for
+// readers already flagged for removal.
+func analyzeClusterMultiAZ(c *awsclient.AuroraClusterInfo, instances map[string]*awsclient.RDSInfo) {
+	c.IsNonProd = isNonProd(c.ClusterID, c.Tags)
+	if !c.IsNonProd || !c.MultiAZ {
+		return
+	}
+
+	var savings float64
+	for _, id := range c.ReaderIDs {
+		if inst, ok := instances[id]; ok && inst.ReplicaAction != replicaActionRemove {
+			savings += inst.EstimatedCost
+		}
+	}
+	if savings <= 0 {
+		return
+	}
+
+	c.MultiAZSavings = stats.Round(savings, 2)
+	c.MultiAZRecommendation = fmt.Sprintf("🧪 Non-production cluster %s runs %d reader(s) across AZs: drop them for Single-AZ, saves $%.2f/month", c.ClusterID, len(c.ReaderIDs), c.MultiAZSavings)
+	if c.Recommendation == "" {
+		c.Recommendation = c.MultiAZRecommendation
+	} else {
+		c.Recommendation += "; " + c.MultiAZRecommendation
+	}
+	c.NeedOptimisation = true
+	c.PotentialSavings += c.MultiAZSavings
+}
+
+// isNonProd reports whether the identifier or an environment tag marks the
+// database as dev, test or staging.
+func isNonProd(id string, tags map[string]string) bool {
+	values := []string{id}
+	for key, value := range tags {
+		for _, k := range environmentTagKeys {
+			if strings.EqualFold(key, k) {
+				values = append(values, value)
+			}
+		}
+	}
+
+	for _, v := range values {
+		tokens := strings.FieldsFunc(strings.ToLower(v), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, t := range tokens {
+			if nonProdTokens[t] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
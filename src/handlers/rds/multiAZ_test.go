@@ -0,0 +1,30 @@
+package rds
+
+import "testing"
+
+func TestIsNonProd(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		tags map[string]string
+		want bool
+	}{
+		{"production id", "orders-prod", nil, false},
+		{"dev id", "orders-dev", nil, true},
+		{"staging id", "staging.orders", nil, true},
+		{"token inside a word", "devices-db", nil, false},
+		{"testing token", "orders_testing_1", nil, true},
+		{"env tag", "orders", map[string]string{"Env": "Staging"}, true},
+		{"environment tag", "orders", map[string]string{"environment": "stg"}, true},
+		{"name tag", "orders", map[string]string{"Name": "orders-test"}, true},
+		{"production tag", "orders", map[string]string{"Environment": "production"}, false},
+		{"other tag ignored", "orders", map[string]string{"team": "dev"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNonProd(tt.id, tt.tags); got != tt.want {
+				t.Errorf("isNonProd(%q, %v) = %v, want %v", tt.id, tt.tags, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,23 +4,73 @@ import (
 	"context"
 	awsclient "cost-optimisation/src/aws"
 	"cost-optimisation/src/shared/constants"
+	"cost-optimisation/src/shared/stats"
 	"cost-optimisation/src/storage"
 	"log"
 	"math"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 )
 
 const (
-	ROOT        = "/Users/c-andrew.mironov/Work/cost-optimisation/"
-	TABLES_PATH = ROOT + "data/rds"
-	TIMEFRAME   = 14
+	ROOT           = "/Users/c-andrew.mironov/Work/cost-optimisation/"
+	TABLES_PATH    = ROOT + "data/rds"
+	CLUSTERS_PATH  = ROOT + "data/aurora"
+	UPGRADES_PATH  = ROOT + "data/rds-upgrades"
+	SNAPSHOTS_PATH = ROOT + "data/rds-snapshots"
+	TIMEFRAME      = 14
+
+	// right-sizing: a class fits when the observed load stays under these
+	// limits on it
+	RIGHT_SIZING_CPU_PERCENTILE      = 95
+	RIGHT_SIZING_NETWORK_PERCENTILE  = 95
+	RIGHT_SIZING_MAX_CPU_PCT         = 60
+	RIGHT_SIZING_MAX_MEMORY_PCT      = 85
+	RIGHT_SIZING_MAX_CONNECTIONS_PCT = 70
+	RIGHT_SIZING_MAX_NETWORK_PCT     = 60
+
+	// storage type: provision for the p99 of hourly peaks plus headroom
+	STORAGE_PERCENTILE        = 99
+	STORAGE_IOPS_HEADROOM_PCT = 20
+
+	// allocated storage: shrink volumes that never use more than half, keeping
+	// headroom and STORAGE_GROWTH_DAYS of growth; warn when full within
+	// STORAGE_FULL_WARNING_DAYS
+	STORAGE_MAX_FREE_PCT      = 50
+	STORAGE_HEADROOM_PCT      = 25
+	STORAGE_GROWTH_DAYS       = 90
+	STORAGE_FULL_WARNING_DAYS = 30
+	STORAGE_MIN_GB            = 20
+
+	// idle: p95 of hourly peaks at or below these over the whole window
+	IDLE_MAX_CONNECTIONS = 1
+	IDLE_MAX_CPU_PCT     = 5
+	IDLE_MAX_IOPS        = 20
+
+	// Aurora: move to I/O-Optimized once I/O is this share of Standard spend
+	AURORA_IO_OPTIMIZED_SHARE_PCT = 25
+
+	// Serverless v2: size min/max ACU from the observed or simulated demand
+	SERVERLESS_ACU_HEADROOM_PCT   = 20
+	SERVERLESS_MIN_ACU_PERCENTILE = 5
+	SERVERLESS_PEGGED_PCT         = 95 // ACUUtilization at which max ACU is too low
+
+	// read replicas: p95 load at or below these means almost no read traffic
+	REPLICA_MAX_CONNECTIONS = 2
+	REPLICA_MAX_CPU_PCT     = 10
+
+	// snapshots and backups
+	SNAPSHOT_MAX_AGE_DAYS             = 90
+	NONPROD_MAX_BACKUP_RETENTION_DAYS = 7
 )
 
+const hoursPerMonth float64 = 720
+
 func AnalyzeRDS() {
 	ctx := context.Background()
 	client := awsclient.NewAWSClient(awsclient.AWSClientOpts{
@@ -29,16 +79,71 @@ func AnalyzeRDS() {
 	})
 
 	rdsMetadata := extractRDSInfo(ctx, client)
+	clusters := extractAuroraClusters(ctx, client, rdsMetadata)
+
+	byID := map[string]*awsclient.RDSInfo{}
+	for i := range rdsMetadata {
+		byID[rdsMetadata[i].InstanceID] = &rdsMetadata[i]
+	}
+
+	now := time.Now()
+	savingsSumm := 0.0
+	for i := range rdsMetadata {
+		rightSizeInstance(&rdsMetadata[i])
+		analyzeGraviton(&rdsMetadata[i])
+		analyzeStorageType(&rdsMetadata[i])
+		analyzeAllocatedStorage(&rdsMetadata[i])
+		analyzeServerless(&rdsMetadata[i])
+		analyzeReadReplica(&rdsMetadata[i], byID)
+		analyzeMultiAZ(&rdsMetadata[i])
+		analyzeExtendedSupport(&rdsMetadata[i], now)
+		// runs last: an idle instance replaces any other advice
+		analyzeIdleInstance(&rdsMetadata[i])
+	}
+
+	for i := range clusters {
+		analyzeAuroraStorageConfig(&clusters[i])
+		analyzeClusterMultiAZ(&clusters[i], byID)
+	}
+
+	snapshots := analyzeSnapshots(ctx, client, rdsMetadata, clusters, now)
+	for _, s := range snapshots {
+		if s.IsOrphaned || s.IsOld {
+			savingsSumm += s.MonthlyCost
+		}
+	}
+	for i := range rdsMetadata {
+		savingsSumm += rdsMetadata[i].PotentialSavings
+	}
+	for i := range clusters {
+		savingsSumm += clusters[i].PotentialSavings
+	}
+
+	// byID points into rdsMetadata, so sort only once the analyzers are done
+	sort.Slice(rdsMetadata, func(i, j int) bool {
+		return rdsMetadata[i].PotentialSavings > rdsMetadata[j].PotentialSavings
+	})
+
+	upgrades := upgradePriorities(rdsMetadata)
+	storage.WriteToJSON(UPGRADES_PATH, upgrades)
+	storage.WriteToCSV(UPGRADES_PATH, upgrades)
+
+	storage.WriteToJSON(SNAPSHOTS_PATH, snapshots)
+	storage.WriteToCSV(SNAPSHOTS_PATH, snapshots)
+
+	storage.WriteToJSON(CLUSTERS_PATH, clusters)
+	storage.WriteToCSV(CLUSTERS_PATH, clusters)
 	storage.WriteToJSON(TABLES_PATH, rdsMetadata)
 	storage.WriteToCSV(TABLES_PATH, rdsMetadata)
+	log.Printf("TOTAL RDS POTENTIAL SAVINGS: $%.2f\n", savingsSumm)
 }
 
-func extractRDSInfo(ctx context.Context, client *awsclient.AWSClient) []RDSInfo {
+func extractRDSInfo(ctx context.Context, client *awsclient.AWSClient) []awsclient.RDSInfo {
 	log.Println("Fetching RDS Metadata...")
 
 	wg := sync.WaitGroup{}
-	ch := make(chan RDSInfo)
-	rdsInfoList := []RDSInfo{}
+	ch := make(chan awsclient.RDSInfo)
+	rdsInfoList := []awsclient.RDSInfo{}
 
 	for _, instanceID := range client.GetRDSInstances(ctx) {
 		wg.Go(func() {
@@ -58,105 +163,126 @@ func extractRDSInfo(ctx context.Context, client *awsclient.AWSClient) []RDSInfo
 	return rdsInfoList
 }
 
-func ProcessRDSInstance(ctx context.Context, client *awsclient.AWSClient, instanceID string) RDSInfo {
+func ProcessRDSInstance(ctx context.Context, client *awsclient.AWSClient, instanceID string) awsclient.RDSInfo {
 	out, err := client.RDS.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
 		DBInstanceIdentifier: aws.String(instanceID),
 	})
 	if err != nil {
 		log.Printf("Error describing RDS instance %s: %v\n", instanceID, err)
-		return RDSInfo{}
+		return awsclient.RDSInfo{}
 	}
 	if len(out.DBInstances) == 0 {
 		log.Printf("RDS instance %s not found\n", instanceID)
-		return RDSInfo{}
+		return awsclient.RDSInfo{}
 	}
 
 	inst := out.DBInstances[0]
 	start := time.Now().Add(-24 * time.Hour * TIMEFRAME)
 	end := time.Now()
 
-	cpuAvg, _ := GetRDSMetric(ctx, client.CloudWatch, instanceID, "CPUUtilization", start, end)
-	storageFree, _ := GetRDSMetric(ctx, client.CloudWatch, instanceID, "FreeStorageSpace", start, end)
-	connections, _ := GetRDSMetric(ctx, client.CloudWatch, instanceID, "DatabaseConnections", start, end)
-	readIOPS, _ := GetRDSMetric(ctx, client.CloudWatch, instanceID, "ReadIOPS", start, end)
-	writeIOPS, _ := GetRDSMetric(ctx, client.CloudWatch, instanceID, "WriteIOPS", start, end)
-
-	ti := RDSInfo{
-		TableName:        instanceID,
-		TableArn:         inst.DBInstanceArn,
-		InstanceType:     *inst.DBInstanceClass,
-		BillingMode:      *inst.Engine,
-		Connections:      connections,
-		TableSizeMB:      storageFree / 1024 / 1024,
-		AvgConsumedRead:  readIOPS,
-		AvgConsumedWrite: writeIOPS,
-		UtilizationPct:   cpuAvg,
-	}
-
-	tableSizeGB := storageFree / 1024 / 1024 / 1024 // convert bytes → GB
-	replicas := len(inst.ReadReplicaDBInstanceIdentifiers)
-	multiAZ := inst.MultiAZ != nil && *inst.MultiAZ
-
-	ti.EstimatedCost = EstimateRDSMonthlyCost(
-		*inst.DBInstanceClass, // instanceType
-		*inst.Engine,          // engine
-		*inst.StorageType,     // storageType
-		tableSizeGB,           // tableSizeGB
-		readIOPS,              // readIOPS
-		writeIOPS,             // writeIOPS
-		replicas,              // replicas
-		multiAZ,               // multiAZ
-		connections,           // connections
-	)
-
-	ti.Recommendation = RecommendRDS(cpuAvg, storageFree)
-	ti.NeedOptimisation = cpuAvg < 10.0 || cpuAvg > 80.0
+	cpuAvg, cpuErr := awsclient.GetRDSMetric(ctx, client.CloudWatch, instanceID, "CPUUtilization", start, end)
+	storageFree, _ := awsclient.GetRDSMetric(ctx, client.CloudWatch, instanceID, "FreeStorageSpace", start, end)
+	connections, _ := awsclient.GetRDSMetric(ctx, client.CloudWatch, instanceID, "DatabaseConnections", start, end)
+	readIOPS, _ := awsclient.GetRDSMetric(ctx, client.CloudWatch, instanceID, "ReadIOPS", start, end)
+	writeIOPS, _ := awsclient.GetRDSMetric(ctx, client.CloudWatch, instanceID, "WriteIOPS", start, end)
+	backupBilled, _ := awsclient.GetRDSMetric(ctx, client.CloudWatch, instanceID, "TotalBackupStorageBilled", start, end)
+
+	hours := 24 * TIMEFRAME
+	cpuSeries, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "CPUUtilization", cwtypes.StatisticAverage, start, hours)
+	memorySeries, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "FreeableMemory", cwtypes.StatisticMinimum, start, hours)
+	connectionsSeries, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "DatabaseConnections", cwtypes.StatisticMaximum, start, hours)
+	receiveSeries, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "NetworkReceiveThroughput", cwtypes.StatisticAverage, start, hours)
+	transmitSeries, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "NetworkTransmitThroughput", cwtypes.StatisticAverage, start, hours)
+	readIOPSSeries, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "ReadIOPS", cwtypes.StatisticMaximum, start, hours)
+	writeIOPSSeries, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "WriteIOPS", cwtypes.StatisticMaximum, start, hours)
+	readThroughputSeries, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "ReadThroughput", cwtypes.StatisticMaximum, start, hours)
+	writeThroughputSeries, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "WriteThroughput", cwtypes.StatisticMaximum, start, hours)
+	freeStorageSeries, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "FreeStorageSpace", cwtypes.StatisticMinimum, start, hours)
+
+	ti := awsclient.NewRDSInfo(inst)
+	ti.AvgCPU = cpuAvg
+	ti.AvgConnections = connections
+	ti.AvgReadIOPS = readIOPS
+	ti.AvgWriteIOPS = writeIOPS
+	ti.MetricsAvailable = cpuErr == nil
+	// percentiles skip the hours CloudWatch did not report, so take them
+	// before the gaps are filled
+	ti.P95CPU = stats.Round(stats.Percentile(cpuSeries, RIGHT_SIZING_CPU_PERCENTILE), 1)
+	ti.P95NetworkMBps = stats.Round(stats.Percentile(addSeries(receiveSeries, transmitSeries), RIGHT_SIZING_NETWORK_PERCENTILE)/1024/1024, 2)
+	ti.CPUSeries = awsclient.ZeroFill(cpuSeries)
+	ti.FreeableMemorySeries = awsclient.ForwardFill(memorySeries)
+	ti.ConnectionsSeries = awsclient.ZeroFill(connectionsSeries)
+	ti.NetworkThroughputSeries = addSeries(awsclient.ZeroFill(receiveSeries), awsclient.ZeroFill(transmitSeries))
+	ti.IOPSSeries = addSeries(awsclient.ZeroFill(readIOPSSeries), awsclient.ZeroFill(writeIOPSSeries))
+	ti.FreeStorageSeries = awsclient.ForwardFill(freeStorageSeries)
+	ti.ThroughputSeries = addSeries(awsclient.ZeroFill(readThroughputSeries), awsclient.ZeroFill(writeThroughputSeries))
+	ti.FreeStorageGB = storageFree / 1024 / 1024 / 1024 // bytes → GB
+	if n := len(ti.FreeStorageSeries); n > 0 {
+		ti.FreeStorageGB = ti.FreeStorageSeries[n-1] / 1024 / 1024 / 1024 // latest
+	}
+	if ti.AllocatedStorageGB > 0 {
+		ti.UsedStorageGB = math.Max(0, float64(ti.AllocatedStorageGB)-ti.FreeStorageGB)
+		ti.FreeStoragePct = stats.Round(100*ti.FreeStorageGB/float64(ti.AllocatedStorageGB), 1)
+	}
+
+	ti.Region = client.Region
+	switch {
+	case ti.ReadReplicaSourceID != "":
+		lag, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "ReplicaLag", cwtypes.StatisticMaximum, start, hours)
+		ti.ReplicaLagSeries = awsclient.ForwardFill(lag)
+	case awsclient.IsAurora(ti.Engine):
+		lag, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "AuroraReplicaLag", cwtypes.StatisticMaximum, start, hours)
+		for i := range lag {
+			lag[i] /= 1000 // ms → s
+		}
+		ti.ReplicaLagSeries = awsclient.ForwardFill(lag)
+	}
+	if ti.InstanceClass == awsclient.ServerlessClass {
+		acu, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "ServerlessDatabaseCapacity", cwtypes.StatisticAverage, start, hours)
+		acuUtilization, _ := awsclient.GetRDSSeries(ctx, client.CloudWatch, instanceID, "ACUUtilization", cwtypes.StatisticMaximum, start, hours)
+		ti.ACUSeries = awsclient.ZeroFill(acu)
+		ti.ACUUtilizationSeries = awsclient.ZeroFill(acuUtilization)
+		ti.AvgACU = stats.Round(mean(ti.ACUSeries), 2)
+		ti.PeakACU = stats.Percentile(ti.ACUSeries, 100)
+	}
+	ti.BackupStorageBilledGB = backupBilled / 1024 / 1024 / 1024
+
+	cost := awsclient.EstimateRDSCost(ti, client.Region)
+	ti.ComputeMonthlyCost = cost.Compute
+	ti.StorageMonthlyCost = cost.Storage
+	ti.IOPSMonthlyCost = cost.IOPS
+	ti.ThroughputMonthlyCost = cost.Throughput
+	ti.BackupMonthlyCost = cost.Backup
+	ti.EstimatedCost = cost.Total
+	ti.PricingSource = cost.PricingSource
+	if cost.PricingSource != awsclient.PricingCatalog {
+		log.Printf("RDS instance %s (%s in %s) priced with %s, cost is an estimate\n", instanceID, ti.InstanceClass, client.Region, cost.PricingSource)
+	}
 
 	return ti
 }
 
-func EstimateRDSMonthlyCost(
-	instanceType, engine, storageType string,
-	tableSizeGB, readIOPS, writeIOPS float64,
-	replicas int, multiAZ bool, connections float64,
-) float64 {
+// addRecommendation appends an analyzer's advice to the instance
+// recommendation and flags the instance.
+func addRecommendation(ti *awsclient.RDSInfo, text string) {
+	if ti.Recommendation == "" {
+		ti.Recommendation = text
+	} else {
+		ti.Recommendation += "; " + text
+	}
+	ti.NeedOptimisation = true
+}
 
-	var hourlyRate float64
-	switch {
-	case strings.Contains(instanceType, "t3.micro"):
-		hourlyRate = 0.017
-	case strings.Contains(instanceType, "r6g.large"):
-		hourlyRate = 0.188
-	case strings.Contains(instanceType, "r6g.xlarge"):
-		hourlyRate = 0.376
-	case strings.Contains(instanceType, "r6g.2xlarge"):
-		hourlyRate = 0.752
-	case strings.Contains(instanceType, "r6g.4xlarge"):
-		hourlyRate = 1.504
-	case strings.Contains(instanceType, "serverless"):
-		// Aurora Serverless v2 rough guess
-		acus := math.Max(2, connections/500.0)
-		return acus * 0.12 * 720
-	default:
-		hourlyRate = 0.10
-	}
-
-	computeCost := hourlyRate * 720 * float64(replicas)
-
-	storageRate := 0.10
-	if engine != "aurora-mysql" && engine != "aurora-postgresql" {
-		storageRate = 0.25
-	}
-	storageCost := tableSizeGB * storageRate
-	if multiAZ {
-		storageCost *= 2
-	}
-
-	iopsCost := 0.0
-	if storageType == "io1" {
-		iopsCost = (readIOPS + writeIOPS) * 0.10
-	}
-
-	total := (computeCost + storageCost + iopsCost) * 1.25 // +25% overhead
-	return total
+// addSeries adds two hourly series; a nil series counts as zero.
+func addSeries(a, b []float64) []float64 {
+	out := make([]float64, max(len(a), len(b)))
+	for i := range out {
+		if i < len(a) {
+			out[i] += a[i]
+		}
+		if i < len(b) {
+			out[i] += b[i]
+		}
+	}
+	return out
 }
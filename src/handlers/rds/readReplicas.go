@@ -0,0 +1,91 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+)
+
+const (
+	replicaActionRemove   = "remove"
+	replicaActionDownsize = "downsize"
+)
+
+// analyzeReadReplica links a read replica (or Aurora reader) to its source,
+// rolls its cost up into the source's ReplicasMonthlyCost and checks whether
+// it serves any reads. Replicas with almost no connections or CPU are removal
+// candidates and their whole cost replaces the other savings; busier replicas
+// that right-sizing found too large are downsize candidates.
+func analyzeReadReplica(ti *awsclient.RDSInfo, instances map[string]*awsclient.RDSInfo) {
+	if ti.ReadReplicaSourceID == "" && ti.ClusterRole != "reader" {
+		return
+	}
+	source := replicaSource(ti, instances)
+	if source != nil {
+		source.ReplicasMonthlyCost = stats.Round(source.ReplicasMonthlyCost+ti.EstimatedCost, 2)
+	}
+	ti.P95ReplicaLagSeconds = stats.Round(stats.Percentile(ti.ReplicaLagSeries, 95), 1)
+
+	// a failed connections fetch would read as zero connections
+	if !ti.MetricsAvailable || len(ti.CPUSeries) == 0 || len(ti.ConnectionsSeries) == 0 {
+		return
+	}
+	connections := stats.Percentile(ti.ConnectionsSeries, 95)
+	cpu := stats.Percentile(ti.CPUSeries, 95)
+
+	switch {
+	case connections <= REPLICA_MAX_CONNECTIONS && cpu <= REPLICA_MAX_CPU_PCT:
+		ti.IsUnderusedReplica = true
+		ti.ReplicaAction = replicaActionRemove
+		ti.ReplicaSavings = ti.EstimatedCost
+		ti.ReplicaRecommendation = fmt.Sprintf("🪞 Replica of %s serves almost no reads (p95 %.0f connections, %.1f%% CPU): remove it, saves $%.2f/month",
+			sourceName(ti), connections, cpu, ti.ReplicaSavings)
+		if ti.ClusterRole == "reader" && source != nil && len(readersOf(source, instances)) == 1 {
+			ti.ReplicaRecommendation += " (the cluster loses its failover target)"
+		}
+		ti.Recommendation = ti.ReplicaRecommendation
+		ti.NeedOptimisation = true
+		ti.PotentialSavings = ti.ReplicaSavings
+	case ti.RightSizingSavings > 0 && ti.RecommendedClass != "":
+		// savings already counted by right-sizing
+		ti.IsUnderusedReplica = true
+		ti.ReplicaAction = replicaActionDownsize
+		ti.ReplicaSavings = ti.RightSizingSavings
+		ti.ReplicaRecommendation = fmt.Sprintf("🪞 Replica of %s is oversized: downsize to %s, saves $%.2f/month", sourceName(ti), ti.RecommendedClass, ti.ReplicaSavings)
+	}
+}
+
+// replicaSource returns the source instance of a read replica or the writer
+// of an Aurora reader's cluster, or nil when it was not scanned (e.g. a
+// cross-region source).
+func replicaSource(ti *awsclient.RDSInfo, instances map[string]*awsclient.RDSInfo) *awsclient.RDSInfo {
+	if ti.ReadReplicaSourceID != "" {
+		return instances[ti.ReadReplicaSourceID]
+	}
+	if ti.ClusterRole != "reader" {
+		return nil
+	}
+	for _, inst := range instances {
+		if inst.ClusterID == ti.ClusterID && inst.ClusterRole == "writer" {
+			return inst
+		}
+	}
+	return nil
+}
+
+func readersOf(writer *awsclient.RDSInfo, instances map[string]*awsclient.RDSInfo) []string {
+	var readers []string
+	for id, inst := range instances {
+		if inst.ClusterID == writer.ClusterID && inst.ClusterRole == "reader" {
+			readers = append(readers, id)
+		}
+	}
+	return readers
+}
+
+func sourceName(ti *awsclient.RDSInfo) string {
+	if ti.ReadReplicaSourceID != "" {
+		return ti.ReadReplicaSourceID
+	}
+	return "cluster " + ti.ClusterID
+}
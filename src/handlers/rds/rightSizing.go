@@ -0,0 +1,98 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"math"
+)
+
+// rightSizeInstance picks the smallest class of the instance's family that
+// still fits its p95 CPU, worst-case memory, peak connections and p95 network
+// throughput, and records the projected utilization and monthly saving.
+// Overloaded instances are moved up the family instead. P95CPU and
+// P95NetworkMBps are taken by the scan. Without a memory series the instance
+// may be upsized but never downsized.
+func rightSizeInstance(ti *awsclient.RDSInfo) {
+	current, ok := awsclient.GetRDSInstanceSpec(ti.InstanceClass)
+	if !ok || !ti.MetricsAvailable || len(ti.CPUSeries) == 0 {
+		return
+	}
+
+	ti.PeakConnections = stats.Percentile(ti.ConnectionsSeries, 100)
+
+	usedMemoryGiB := 0.0
+	memoryKnown := len(ti.FreeableMemorySeries) > 0
+	if memoryKnown {
+		minFree := stats.Percentile(ti.FreeableMemorySeries, 0)
+		ti.MinFreeableMemoryGB = stats.Round(minFree/1024/1024/1024, 2)
+		usedMemoryGiB = math.Max(0, current.MemoryGiB-ti.MinFreeableMemoryGB)
+	}
+
+	cpuVCPUs := ti.P95CPU / 100 * float64(current.VCPU) // busy vCPUs at p95
+	networkGbps := ti.P95NetworkMBps * 8 / 1000
+
+	fits := func(spec awsclient.RDSInstanceSpec) (cpu, memory, connections float64, ok bool) {
+		cpu = 100 * cpuVCPUs / float64(spec.VCPU)
+		memory = 100 * usedMemoryGiB / spec.MemoryGiB
+		connections = 100 * ti.PeakConnections / awsclient.RDSMaxConnections(ti.Engine, spec.MemoryGiB)
+		network := 100 * networkGbps / spec.NetworkGbps
+		ok = cpu <= RIGHT_SIZING_MAX_CPU_PCT &&
+			memory <= RIGHT_SIZING_MAX_MEMORY_PCT &&
+			connections <= RIGHT_SIZING_MAX_CONNECTIONS_PCT &&
+			network <= RIGHT_SIZING_MAX_NETWORK_PCT
+		return cpu, memory, connections, ok
+	}
+
+	var (
+		target                   awsclient.RDSInstanceSpec
+		cpu, memory, connections float64
+		found                    bool
+	)
+	for _, class := range awsclient.RDSFamilyClasses(current.Family) {
+		if _, priced := awsclient.RDSHourlyRate(class, ti.Engine, ti.LicenseModel, ti.Region); !priced {
+			continue // not offered for this engine
+		}
+		spec, _ := awsclient.GetRDSInstanceSpec(class)
+		if cpu, memory, connections, found = fits(spec); found {
+			target = spec
+			break
+		}
+	}
+	if !found || target.Class == ti.InstanceClass {
+		return
+	}
+	upsize := target.VCPU > current.VCPU || target.MemoryGiB > current.MemoryGiB
+	if !upsize && !memoryKnown {
+		return
+	}
+
+	alt := *ti
+	alt.InstanceClass = target.Class
+	savings := ti.ComputeMonthlyCost - awsclient.EstimateRDSCost(alt, ti.Region).Compute
+
+	ti.RecommendedClass = target.Class
+	ti.ProjectedCPU = stats.Round(cpu, 1)
+	ti.ProjectedMemoryPct = stats.Round(memory, 1)
+	ti.ProjectedConnectionsPct = stats.Round(connections, 1)
+	ti.RightSizingSavings = stats.Round(savings, 2)
+
+	direction := "Downsize"
+	if upsize {
+		direction = "Upsize"
+	}
+	ti.RightSizingRecommendation = fmt.Sprintf("📐 %s %s → %s (p95 CPU %.0f%%, memory %.0f%%, connections %.0f%% on new class, %s)",
+		direction, ti.InstanceClass, target.Class, ti.ProjectedCPU, ti.ProjectedMemoryPct, ti.ProjectedConnectionsPct, savingsText(savings))
+	ti.Recommendation = ti.RightSizingRecommendation
+	ti.NeedOptimisation = true
+	if savings > 0 {
+		ti.PotentialSavings += stats.Round(savings, 2)
+	}
+}
+
+func savingsText(savings float64) string {
+	if savings < 0 {
+		return fmt.Sprintf("+$%.2f/month", -savings)
+	}
+	return fmt.Sprintf("saves $%.2f/month", savings)
+}
@@ -0,0 +1,140 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"math"
+)
+
+const (
+	serverlessMinACU = 0.5
+	serverlessMaxACU = 256
+	gibPerACU        = 2
+)
+
+// analyzeServerless models Aurora Serverless v2 from hourly usage. Serverless
+// instances get min/max ACU settings from their ServerlessDatabaseCapacity and
+// ACUUtilization series; provisioned Aurora instances get the cost of the same
+// workload on Serverless v2, simulated from their CPU and memory series.
+func analyzeServerless(ti *awsclient.RDSInfo) {
+	if !awsclient.IsAurora(ti.Engine) {
+		return
+	}
+	if ti.InstanceClass == awsclient.ServerlessClass {
+		tuneServerless(ti)
+	} else {
+		simulateServerless(ti)
+	}
+}
+
+// tuneServerless recommends min and max ACU for a Serverless v2 instance. Max
+// ACU covers the observed peak plus headroom, or is raised when the instance
+// was pegged at its max; min ACU drops to the low percentile of demand, and
+// the hours spent at the current floor are assumed to need no more than that.
+func tuneServerless(ti *awsclient.RDSInfo) {
+	if len(ti.ACUSeries) == 0 {
+		return
+	}
+
+	headroom := 1 + SERVERLESS_ACU_HEADROOM_PCT/100.0
+	recMin := math.Max(serverlessMinACU, floorHalf(stats.Percentile(ti.ACUSeries, SERVERLESS_MIN_ACU_PERCENTILE)))
+	recMax := ceilHalf(ti.PeakACU * headroom)
+	if ti.MaxACU > 0 && stats.Percentile(ti.ACUUtilizationSeries, 99) >= SERVERLESS_PEGGED_PCT {
+		recMax = ceilHalf(ti.MaxACU * 1.5)
+	}
+	recMax = math.Min(serverlessMaxACU, math.Max(recMax, recMin))
+
+	simulated := make([]float64, len(ti.ACUSeries))
+	for h, acu := range ti.ACUSeries {
+		if ti.MinACU > 0 && acu <= ti.MinACU {
+			acu = recMin
+		}
+		simulated[h] = math.Min(recMax, math.Max(recMin, acu))
+	}
+
+	rate := awsclient.AuroraACUHourlyRate(ti.StorageType, ti.Region)
+	cost := mean(simulated) * rate * hoursPerMonth
+	savings := ti.ComputeMonthlyCost - cost
+
+	ti.RecommendedMinACU = recMin
+	ti.RecommendedMaxACU = recMax
+	ti.ServerlessMonthlyCost = stats.Round(cost, 2)
+	if recMin == ti.MinACU && recMax == ti.MaxACU {
+		return
+	}
+
+	ti.ServerlessSavings = stats.Round(savings, 2)
+	ti.ServerlessRecommendation = fmt.Sprintf("🌀 Set ACU range %.1f–%.1f (now %.1f–%.1f, avg %.1f, peak %.1f, %s)",
+		recMin, recMax, ti.MinACU, ti.MaxACU, ti.AvgACU, ti.PeakACU, savingsText(savings))
+	addRecommendation(ti, ti.ServerlessRecommendation)
+	if savings > 0 {
+		ti.PotentialSavings += ti.ServerlessSavings
+	}
+}
+
+// simulateServerless prices a provisioned Aurora instance's hourly demand on
+// Serverless v2. An ACU is 2 GiB of memory with proportional CPU, so each
+// hour needs enough ACUs for both its busy vCPUs and its used memory. It is
+// compared with the instance after right-sizing and Graviton advice, so only
+// the extra saving is counted.
+func simulateServerless(ti *awsclient.RDSInfo) {
+	spec, ok := awsclient.GetRDSInstanceSpec(ti.InstanceClass)
+	if !ok || !ti.MetricsAvailable || len(ti.CPUSeries) == 0 {
+		return
+	}
+
+	acuPerVCPU := spec.MemoryGiB / float64(spec.VCPU) / gibPerACU
+	headroom := 1 + SERVERLESS_ACU_HEADROOM_PCT/100.0
+
+	demand := make([]float64, len(ti.CPUSeries))
+	for h, cpu := range ti.CPUSeries {
+		acu := cpu / 100 * float64(spec.VCPU) * acuPerVCPU
+		if h < len(ti.FreeableMemorySeries) {
+			used := spec.MemoryGiB - ti.FreeableMemorySeries[h]/bytesPerGB
+			acu = math.Max(acu, used/gibPerACU)
+		}
+		demand[h] = acu * headroom
+	}
+
+	recMin := math.Max(serverlessMinACU, floorHalf(stats.Percentile(demand, SERVERLESS_MIN_ACU_PERCENTILE)))
+	recMax := math.Min(serverlessMaxACU, math.Max(recMin, ceilHalf(stats.Percentile(demand, 100))))
+
+	var acuHours float64
+	for _, acu := range demand {
+		acuHours += math.Min(recMax, math.Max(recMin, ceilHalf(acu)))
+	}
+
+	rate := awsclient.AuroraACUHourlyRate(ti.StorageType, ti.Region)
+	cost := acuHours / float64(len(demand)) * rate * hoursPerMonth
+	provisioned := ti.ComputeMonthlyCost - max(0, ti.RightSizingSavings) - max(0, ti.GravitonSavings)
+	savings := provisioned - cost
+
+	ti.RecommendedMinACU = recMin
+	ti.RecommendedMaxACU = recMax
+	ti.ServerlessMonthlyCost = stats.Round(cost, 2)
+	ti.ServerlessSavings = stats.Round(savings, 2)
+	if savings <= 0 {
+		return
+	}
+
+	ti.ServerlessRecommendation = fmt.Sprintf("🌀 Move %s to Serverless v2 with %.1f–%.1f ACU ($%.2f vs $%.2f per month, saves $%.2f/month)",
+		ti.InstanceClass, recMin, recMax, cost, provisioned, savings)
+	addRecommendation(ti, ti.ServerlessRecommendation)
+	ti.PotentialSavings += ti.ServerlessSavings
+}
+
+func floorHalf(v float64) float64 {
+	return math.Floor(v*2) / 2
+}
+
+func ceilHalf(v float64) float64 {
+	return math.Ceil(v*2) / 2
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return sum(values) / float64(len(values))
+}
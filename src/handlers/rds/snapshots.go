@@ -0,0 +1,203 @@
+package rds
+
+import (
+	"context"
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"log"
+	"time"
+)
+
+const snapshotTypeManual = "manual"
+
+// analyzeSnapshots lists the instance and cluster snapshots of the region,
+// prices them and flags non-production backup retention. Snapshot sources
+// missing from the scan may live in another region or have failed to
+// describe, so only RDS itself can tell they are gone.
+func analyzeSnapshots(ctx context.Context, client *awsclient.AWSClient, instances []awsclient.RDSInfo, clusters []awsclient.AuroraClusterInfo, now time.Time) []awsclient.RDSSnapshotInfo {
+	snapshots := []awsclient.RDSSnapshotInfo{}
+	for _, s := range client.GetRDSSnapshots(ctx) {
+		snapshots = append(snapshots, awsclient.NewRDSSnapshotInfo(s))
+	}
+	for _, s := range client.GetRDSClusterSnapshots(ctx) {
+		snapshots = append(snapshots, awsclient.NewRDSClusterSnapshotInfo(s))
+	}
+
+	orphaned := map[string]bool{}
+	isOrphaned := func(s awsclient.RDSSnapshotInfo) bool {
+		key := s.SourceRegion + "/" + snapshotKey(s)
+		if gone, ok := orphaned[key]; ok {
+			return gone
+		}
+		exists, err := client.RDSSourceExists(ctx, s.SourceRegion, s.SourceID, s.IsCluster)
+		if err != nil {
+			log.Printf("Failed to look up snapshot source %s: %v\n", s.SourceID, err)
+		}
+		orphaned[key] = err == nil && !exists
+		return orphaned[key]
+	}
+	oldSnapshotCost := priceSnapshots(snapshots, instances, clusters, client.Region, now, isOrphaned)
+
+	for i := range instances {
+		analyzeInstanceRetention(&instances[i], oldSnapshotCost[sourceKey(instances[i].InstanceID, false)])
+	}
+	for i := range clusters {
+		analyzeClusterRetention(&clusters[i], oldSnapshotCost[sourceKey(clusters[i].ClusterID, true)])
+	}
+	return snapshots
+}
+
+// priceSnapshots estimates the storage cost of snapshots. Snapshots are
+// incremental and only backup storage beyond the free allowance is billed,
+// so a source's billed backup storage (TotalBackupStorageBilled) is shared
+// across its snapshots. Orphaned snapshots, whose source isOrphaned confirms
+// no longer exists, and copies from other regions are billed in full. Manual
+// snapshots older than SNAPSHOT_MAX_AGE_DAYS are flagged, and each instance
+// and cluster gets its snapshot count and cost. The cost of old snapshots is
+// returned by source, as it is left out of the source's retention savings,
+// which come from the same billed backup storage.
+func priceSnapshots(snapshots []awsclient.RDSSnapshotInfo, instances []awsclient.RDSInfo, clusters []awsclient.AuroraClusterInfo, region string, now time.Time, isOrphaned func(awsclient.RDSSnapshotInfo) bool) map[string]float64 {
+	instanceByID := map[string]*awsclient.RDSInfo{}
+	clusterIDs := map[string]bool{}
+	for i := range instances {
+		instanceByID[instances[i].InstanceID] = &instances[i]
+		if instances[i].ClusterID != "" {
+			clusterIDs[instances[i].ClusterID] = true // includes Multi-AZ DB clusters
+		}
+	}
+	clusterByID := map[string]*awsclient.AuroraClusterInfo{}
+	for i := range clusters {
+		clusterByID[clusters[i].ClusterID] = &clusters[i]
+		clusterIDs[clusters[i].ClusterID] = true
+	}
+
+	// copies from other regions are not part of the source's backup storage
+	counts := map[string]int{}
+	for _, s := range snapshots {
+		if isLocal(s, region) {
+			counts[snapshotKey(s)]++
+		}
+	}
+
+	oldSnapshotCost := map[string]float64{}
+	for i := range snapshots {
+		s := &snapshots[i]
+		if s.CreateTime != nil {
+			s.AgeDays = int(now.Sub(*s.CreateTime).Hours() / 24)
+		}
+
+		var (
+			inst     *awsclient.RDSInfo
+			cluster  *awsclient.AuroraClusterInfo
+			scanned  bool
+			billedGB float64
+		)
+		local := isLocal(*s, region)
+		switch {
+		case local && s.IsCluster:
+			cluster = clusterByID[s.SourceID]
+			scanned = clusterIDs[s.SourceID]
+		case local:
+			inst = instanceByID[s.SourceID]
+			scanned = inst != nil
+		}
+		switch {
+		case inst != nil:
+			billedGB = inst.BackupStorageBilledGB / float64(counts[snapshotKey(*s)])
+		case cluster != nil:
+			billedGB = cluster.BackupStorageBilledGB / float64(counts[snapshotKey(*s)])
+		}
+
+		s.IsOrphaned = !scanned && isOrphaned(*s)
+		if s.IsOrphaned || !local {
+			billedGB = float64(s.AllocatedStorageGB)
+		}
+		s.EstimatedBilledGB = stats.Round(billedGB, 2)
+		s.MonthlyCost = stats.Round(awsclient.RDSBackupStorageCost(billedGB, region), 2)
+		s.IsOld = s.SnapshotType == snapshotTypeManual && s.AgeDays > SNAPSHOT_MAX_AGE_DAYS
+		if s.IsOld && (inst != nil || cluster != nil) {
+			oldSnapshotCost[snapshotKey(*s)] += s.MonthlyCost
+		}
+
+		switch {
+		case s.IsOrphaned:
+			s.Recommendation = fmt.Sprintf("🗑️ Orphaned: %s no longer exists, delete or archive to S3 (saves $%.2f/month)", s.SourceID, s.MonthlyCost)
+		case s.IsOld:
+			s.Recommendation = fmt.Sprintf("🗑️ Manual snapshot is %d days old: delete if no longer needed (saves ~$%.2f/month)", s.AgeDays, s.MonthlyCost)
+		}
+
+		if inst != nil {
+			inst.SnapshotCount++
+			inst.SnapshotMonthlyCost = stats.Round(inst.SnapshotMonthlyCost+s.MonthlyCost, 2)
+		}
+		if cluster != nil {
+			cluster.SnapshotCount++
+			cluster.SnapshotMonthlyCost = stats.Round(cluster.SnapshotMonthlyCost+s.MonthlyCost, 2)
+		}
+	}
+	return oldSnapshotCost
+}
+
+func isLocal(s awsclient.RDSSnapshotInfo, region string) bool {
+	return s.SourceRegion == "" || s.SourceRegion == region
+}
+
+func snapshotKey(s awsclient.RDSSnapshotInfo) string {
+	return sourceKey(s.SourceID, s.IsCluster)
+}
+
+func sourceKey(id string, cluster bool) string {
+	if cluster {
+		return "cluster/" + id
+	}
+	return "instance/" + id
+}
+
+// analyzeInstanceRetention flags non-production instances keeping automated
+// backups longer than NONPROD_MAX_BACKUP_RETENTION_DAYS. Billed backup storage
+// is assumed to grow with the retention period. Idle instances and replicas
+// flagged for removal already count their whole cost, and oldSnapshotCost is
+// the part of the backup cost already counted by old manual snapshots.
+func analyzeInstanceRetention(ti *awsclient.RDSInfo, oldSnapshotCost float64) {
+	if !ti.IsNonProd || ti.IsIdle || ti.ReplicaAction == replicaActionRemove {
+		return
+	}
+	savings, text := retentionSavings(ti.BackupRetentionDays, ti.BackupMonthlyCost-oldSnapshotCost)
+	if savings <= 0 {
+		return
+	}
+	ti.BackupRetentionSavings = savings
+	ti.BackupRetentionRecommendation = text
+	addRecommendation(ti, text)
+	ti.PotentialSavings += savings
+}
+
+func analyzeClusterRetention(c *awsclient.AuroraClusterInfo, oldSnapshotCost float64) {
+	if !c.IsNonProd {
+		return
+	}
+	savings, text := retentionSavings(c.BackupRetentionDays, c.BackupMonthlyCost-oldSnapshotCost)
+	if savings <= 0 {
+		return
+	}
+	c.BackupRetentionSavings = savings
+	c.BackupRetentionRecommendation = text
+	if c.Recommendation == "" {
+		c.Recommendation = text
+	} else {
+		c.Recommendation += "; " + text
+	}
+	c.NeedOptimisation = true
+	c.PotentialSavings += savings
+}
+
+func retentionSavings(retentionDays int32, backupCost float64) (float64, string) {
+	if retentionDays <= NONPROD_MAX_BACKUP_RETENTION_DAYS || backupCost <= 0 {
+		return 0, ""
+	}
+	excess := float64(retentionDays-NONPROD_MAX_BACKUP_RETENTION_DAYS) / float64(retentionDays)
+	savings := stats.Round(backupCost*excess, 2)
+	return savings, fmt.Sprintf("🗄️ Non-production backup retention is %d days: reduce to %d, saves $%.2f/month",
+		retentionDays, NONPROD_MAX_BACKUP_RETENTION_DAYS, savings)
+}
@@ -0,0 +1,95 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"testing"
+	"time"
+)
+
+func TestRetentionSavings(t *testing.T) {
+	tests := []struct {
+		name          string
+		retentionDays int32
+		backupCost    float64
+		want          float64
+	}{
+		{"within the limit", NONPROD_MAX_BACKUP_RETENTION_DAYS, 10, 0},
+		{"no backup cost", 35, 0, 0},
+		{"two weeks", 14, 10, 5},
+		{"five weeks", 35, 10, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, text := retentionSavings(tt.retentionDays, tt.backupCost)
+			if got != tt.want {
+				t.Errorf("retentionSavings(%d, %v) = %v, want %v", tt.retentionDays, tt.backupCost, got, tt.want)
+			}
+			if (text != "") != (tt.want > 0) {
+				t.Errorf("retentionSavings(%d, %v) text = %q", tt.retentionDays, tt.backupCost, text)
+			}
+		})
+	}
+}
+
+func TestPriceSnapshots(t *testing.T) {
+	const region = "us-east-1"
+	now := time.Now()
+	recent := now.AddDate(0, 0, -1)
+	old := now.AddDate(0, 0, -(SNAPSHOT_MAX_AGE_DAYS + 10))
+
+	instances := []awsclient.RDSInfo{{InstanceID: "db-1", BackupStorageBilledGB: 40}}
+	clusters := []awsclient.AuroraClusterInfo{{ClusterID: "cluster-1", BackupStorageBilledGB: 8}}
+	snapshots := []awsclient.RDSSnapshotInfo{
+		{SnapshotID: "auto-1", SourceID: "db-1", SnapshotType: "automated", CreateTime: &recent},
+		{SnapshotID: "auto-2", SourceID: "db-1", SnapshotType: "automated", CreateTime: &recent},
+		{SnapshotID: "auto-3", SourceID: "db-1", SnapshotType: "automated", CreateTime: &recent},
+		{SnapshotID: "manual-old", SourceID: "db-1", SnapshotType: snapshotTypeManual, CreateTime: &old},
+		{SnapshotID: "cluster-auto", SourceID: "cluster-1", IsCluster: true, SnapshotType: "automated", CreateTime: &recent},
+		{SnapshotID: "orphan", SourceID: "gone", SnapshotType: snapshotTypeManual, CreateTime: &recent, AllocatedStorageGB: 50},
+		{SnapshotID: "unscanned", SourceID: "elsewhere", SnapshotType: snapshotTypeManual, CreateTime: &recent, AllocatedStorageGB: 50},
+		{SnapshotID: "copy", SourceID: "db-1", SourceRegion: "eu-west-1", SnapshotType: snapshotTypeManual, CreateTime: &recent, AllocatedStorageGB: 20},
+	}
+	isOrphaned := func(s awsclient.RDSSnapshotInfo) bool { return s.SourceID == "gone" }
+
+	oldSnapshotCost := priceSnapshots(snapshots, instances, clusters, region, now, isOrphaned)
+
+	tests := []struct {
+		snapshot     awsclient.RDSSnapshotInfo
+		wantBilledGB float64
+		wantOrphaned bool
+		wantOld      bool
+	}{
+		{snapshots[0], 10, false, false},
+		{snapshots[3], 10, false, true},
+		{snapshots[4], 8, false, false},
+		{snapshots[5], 50, true, false},
+		{snapshots[6], 0, false, false},
+		{snapshots[7], 20, false, false},
+	}
+	for _, tt := range tests {
+		s := tt.snapshot
+		if s.EstimatedBilledGB != tt.wantBilledGB {
+			t.Errorf("%s: EstimatedBilledGB = %v, want %v", s.SnapshotID, s.EstimatedBilledGB, tt.wantBilledGB)
+		}
+		if want := stats.Round(awsclient.RDSBackupStorageCost(tt.wantBilledGB, region), 2); s.MonthlyCost != want {
+			t.Errorf("%s: MonthlyCost = %v, want %v", s.SnapshotID, s.MonthlyCost, want)
+		}
+		if s.IsOrphaned != tt.wantOrphaned || s.IsOld != tt.wantOld {
+			t.Errorf("%s: IsOrphaned = %v, IsOld = %v, want %v, %v", s.SnapshotID, s.IsOrphaned, s.IsOld, tt.wantOrphaned, tt.wantOld)
+		}
+	}
+
+	if instances[0].SnapshotCount != 4 {
+		t.Errorf("instance SnapshotCount = %d, want 4", instances[0].SnapshotCount)
+	}
+	if want := stats.Round(4*stats.Round(awsclient.RDSBackupStorageCost(10, region), 2), 2); instances[0].SnapshotMonthlyCost != want {
+		t.Errorf("instance SnapshotMonthlyCost = %v, want %v", instances[0].SnapshotMonthlyCost, want)
+	}
+	if want := snapshots[3].MonthlyCost; oldSnapshotCost[sourceKey("db-1", false)] != want {
+		t.Errorf("old snapshot cost of db-1 = %v, want %v", oldSnapshotCost[sourceKey("db-1", false)], want)
+	}
+	if clusters[0].SnapshotCount != 1 {
+		t.Errorf("cluster SnapshotCount = %d, want 1", clusters[0].SnapshotCount)
+	}
+}
@@ -0,0 +1,122 @@
+package rds
+
+import (
+	awsclient "cost-optimisation/src/aws"
+	"cost-optimisation/src/shared/stats"
+	"fmt"
+	"math"
+)
+
+// RDS volume limits
+const (
+	io2MinIOPS      = 1000
+	io2MaxIOPS      = 256000
+	io2MinGB        = 100
+	io2MaxIOPSPerGB = 1000
+
+	// io2 throughput is not provisioned: each IOPS adds 256 KiB/s
+	io2ThroughputPerIOPS = 0.256 // MiB/s
+	io2MaxThroughput     = 4000  // MiB/s
+)
+
+type storageOption struct {
+	storageType string
+	storageGB   int64
+	iops        int64
+	throughput  int64
+	cost        float64
+}
+
+// analyzeStorageType prices the p99 of hourly IOPS and throughput peaks, plus
+// STORAGE_IOPS_HEADROOM_PCT, on gp3 and io2 and recommends the cheapest volume
+// that covers it. Aurora storage is handled at cluster level.
+func analyzeStorageType(ti *awsclient.RDSInfo) {
+	if awsclient.IsAurora(ti.Engine) || ti.AllocatedStorageGB == 0 || len(ti.IOPSSeries) == 0 {
+		return
+	}
+
+	ti.P99IOPS = stats.Round(stats.Percentile(ti.IOPSSeries, STORAGE_PERCENTILE), 0)
+	ti.P99ThroughputMBps = stats.Round(stats.Percentile(ti.ThroughputSeries, STORAGE_PERCENTILE)/1024/1024, 1)
+
+	headroom := 1 + STORAGE_IOPS_HEADROOM_PCT/100.0
+	needIOPS := int64(math.Ceil(ti.P99IOPS * headroom))
+	needThroughput := int64(math.Ceil(ti.P99ThroughputMBps * headroom))
+
+	current := ti.StorageMonthlyCost + ti.IOPSMonthlyCost + ti.ThroughputMonthlyCost
+	var options []storageOption
+
+	// gp3: only volumes of the engine's ProvisionedMinGB or more can go above
+	// the baseline
+	gp3 := awsclient.GetGP3Limits(ti.Engine)
+	if needIOPS <= gp3.MaxIOPS && needThroughput <= gp3.MaxThroughput {
+		gb := ti.AllocatedStorageGB
+		baseIOPS, baseThroughput := awsclient.GP3Baseline(ti.Engine, float64(gb))
+		if (float64(needIOPS) > baseIOPS || float64(needThroughput) > baseThroughput) && gb < gp3.ProvisionedMinGB {
+			gb = gp3.ProvisionedMinGB
+			baseIOPS, baseThroughput = awsclient.GP3Baseline(ti.Engine, float64(gb))
+		}
+		options = append(options, storageCost(ti, storageOption{
+			storageType: "gp3",
+			storageGB:   gb,
+			iops:        int64(math.Max(float64(needIOPS), baseIOPS)),
+			throughput:  int64(math.Max(float64(needThroughput), baseThroughput)),
+		}))
+	}
+
+	// io2: enough IOPS to carry the throughput too, and IOPS can not exceed
+	// io2MaxIOPSPerGB per GB of storage
+	if needIOPS <= io2MaxIOPS && needThroughput <= io2MaxThroughput {
+		iops := max(needIOPS, io2MinIOPS, int64(math.Ceil(float64(needThroughput)/io2ThroughputPerIOPS)))
+		gb := max(ti.AllocatedStorageGB, io2MinGB, (iops+io2MaxIOPSPerGB-1)/io2MaxIOPSPerGB)
+		options = append(options, storageCost(ti, storageOption{storageType: "io2", storageGB: gb, iops: iops}))
+	}
+
+	if len(options) == 0 {
+		return
+	}
+	best := options[0]
+	for _, o := range options[1:] {
+		if o.cost < best.cost {
+			best = o
+		}
+	}
+
+	// already on the cheapest type with matching IOPS
+	if best.storageType == ti.StorageType && best.iops == ti.ProvisionedIOPS && best.storageGB == ti.AllocatedStorageGB {
+		return
+	}
+
+	delta := best.cost - current
+	ti.RecommendedStorageType = best.storageType
+	ti.RecommendedStorageGB = best.storageGB
+	ti.RecommendedIOPS = best.iops
+	ti.RecommendedThroughput = best.throughput
+	ti.StorageMigrationDelta = stats.Round(delta, 2)
+
+	target := fmt.Sprintf("%s %d GB, %d IOPS", best.storageType, best.storageGB, best.iops)
+	if best.throughput > 0 {
+		target += fmt.Sprintf(", %d MiB/s", best.throughput)
+	}
+	ti.StorageMigrationRecommendation = fmt.Sprintf("💾 Move %s storage to %s for p99 %.0f IOPS / %.1f MiB/s (%s)",
+		ti.StorageType, target, ti.P99IOPS, ti.P99ThroughputMBps, savingsText(-delta))
+
+	if ti.StorageType != best.storageType || delta < 0 {
+		addRecommendation(ti, ti.StorageMigrationRecommendation)
+	}
+	if delta < 0 {
+		ti.PotentialSavings += stats.Round(-delta, 2)
+	}
+}
+
+// storageCost prices a volume option for the instance, including its
+// deployment and region.
+func storageCost(ti *awsclient.RDSInfo, o storageOption) storageOption {
+	alt := *ti
+	alt.StorageType = o.storageType
+	alt.AllocatedStorageGB = o.storageGB
+	alt.ProvisionedIOPS = o.iops
+	alt.StorageThroughput = o.throughput
+	cost := awsclient.EstimateRDSCost(alt, ti.Region)
+	o.cost = cost.Storage + cost.IOPS + cost.Throughput
+	return o
+}
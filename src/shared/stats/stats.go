@@ -0,0 +1,30 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Round rounds val to precision decimal places.
+func Round(val float64, precision int) float64 {
+	p := math.Pow(10, float64(precision))
+	return math.Round(val*p) / p
+}
+
+// Percentile returns the p-th percentile (0-100) of values using the
+// nearest-rank method; NaN values are ignored.
+func Percentile(values []float64, p float64) float64 {
+	sorted := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sorted = append(sorted, v)
+		}
+	}
+	if len(sorted) == 0 {
+		return 0
+	}
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name   string
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{"empty", nil, 95, 0},
+		{"single", []float64{7}, 50, 7},
+		{"median", []float64{5, 1, 3, 2, 4}, 50, 3},
+		{"p95 nearest rank", []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 95, 10},
+		{"p0 is the minimum", []float64{4, 2, 8}, 0, 2},
+		{"p100 is the maximum", []float64{4, 2, 8}, 100, 8},
+		{"NaN skipped", []float64{nan, 1, nan, 3, 2}, 50, 2},
+		{"NaN not counted in rank", []float64{nan, nan, nan, 10, 20}, 95, 20},
+		{"all NaN", []float64{nan, nan}, 95, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.values, tt.p); got != tt.want {
+				t.Errorf("Percentile(%v, %v) = %v, want %v", tt.values, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentileKeepsInput(t *testing.T) {
+	values := []float64{3, 1, 2}
+	Percentile(values, 50)
+	if values[0] != 3 || values[1] != 1 || values[2] != 2 {
+		t.Errorf("Percentile reordered its input: %v", values)
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		val       float64
+		precision int
+		want      float64
+	}{
+		{1.234, 2, 1.23},
+		{1.235, 1, 1.2},
+		{2.5, 0, 3},
+		{-1.26, 1, -1.3},
+		{123.456, 0, 123},
+	}
+	for _, tt := range tests {
+		if got := Round(tt.val, tt.precision); got != tt.want {
+			t.Errorf("Round(%v, %d) = %v, want %v", tt.val, tt.precision, got, tt.want)
+		}
+	}
+}
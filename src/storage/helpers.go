@@ -8,6 +8,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 func WriteToCSV(filePath string, data any) error {
@@ -36,7 +37,10 @@ func WriteToCSV(filePath string, data any) error {
 	var headers []string
 	for i := 0; i < elemType.NumField(); i++ {
 		field := elemType.Field(i)
-		tag := field.Tag.Get("json")
+		if skipCSVField(field) {
+			continue
+		}
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
 		if tag == "" {
 			headers = append(headers, field.Name)
 		} else {
@@ -52,6 +56,9 @@ func WriteToCSV(filePath string, data any) error {
 		rowVal := v.Index(i)
 		var record []string
 		for j := 0; j < rowVal.NumField(); j++ {
+			if skipCSVField(elemType.Field(j)) {
+				continue
+			}
 			field := rowVal.Field(j).Interface()
 			switch val := field.(type) {
 			case string:
@@ -69,7 +76,7 @@ func WriteToCSV(filePath string, data any) error {
 			case bool:
 				record = append(record, strconv.FormatBool(val))
 			default:
-				record = append(record, fmt.Sprintf("%v", val))
+				record = append(record, formatCSVValue(val))
 			}
 		}
 
@@ -82,6 +89,65 @@ func WriteToCSV(filePath string, data any) error {
 	return nil
 }
 
+// skipCSVField drops fields tagged `csv:"-"`, which only belong in the JSON
+// output.
+func skipCSVField(field reflect.StructField) bool {
+	return field.Tag.Get("csv") == "-"
+}
+
+// formatCSVValue writes nested values (slices, maps, structs) as JSON so
+// child records such as GSIs stay readable in a single cell.
+func formatCSVValue(val any) string {
+	switch reflect.ValueOf(val).Kind() {
+	case reflect.Slice, reflect.Map, reflect.Struct, reflect.Pointer:
+		bytes, err := json.Marshal(csvView(reflect.ValueOf(val)))
+		if err == nil {
+			return string(bytes)
+		}
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+// csvView mirrors a nested value for JSON encoding, leaving out fields
+// tagged `csv:"-"` at every level.
+func csvView(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return csvView(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = csvView(v.Index(i))
+		}
+		return items
+	case reflect.Struct:
+		if _, ok := v.Interface().(json.Marshaler); ok {
+			return v.Interface()
+		}
+		fields := map[string]any{}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || skipCSVField(field) {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" {
+				name = field.Name
+			}
+			fields[name] = csvView(v.Field(i))
+		}
+		return fields
+	default:
+		return v.Interface()
+	}
+}
+
 func ReadFile[T any](filePath string) T {
 	log.Println("Reading file" + filePath)
 	var data T